@@ -1,11 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/ojihalawa/daily-coffee-api.git/internal/config"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/delivery/worker"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/migration"
-	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
 )
 
@@ -17,9 +22,10 @@ func main() {
 	app := config.NewFiber(viperConfig)
 	jwtMaker := utils.NewJWTMaker(viperConfig)
 	cloudinary := config.NewCloudinary(viperConfig)
-	midClient := config.NewMidtransClient(viperConfig)
-	midtransService := service.NewMidtransService(midClient)
+	paymentProvider := config.NewPaymentProvider(viperConfig, log)
 	redisClient := config.NewRedisClient(viperConfig, log)
+	workerRunner := worker.NewRunner(log)
+	storeLocation := config.NewStoreLocation(viperConfig, log)
 
 	migration.Run(db, log)
 
@@ -31,13 +37,31 @@ func main() {
 		Config:      viperConfig,
 		JWTMaker:    jwtMaker,
 		Cloudinary:  cloudinary,
-		Midtrans:    midtransService,
+		Payment:     paymentProvider,
 		RedisClient: redisClient,
+		Worker:      workerRunner,
+		Location:    storeLocation,
 	})
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	workerRunner.Start(ctx)
+
+	go func() {
+		<-ctx.Done()
+		log.Info("Shutting down server...")
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			log.Warnf("Failed to shutdown server: %v", err)
+		}
+	}()
+
 	webPort := viperConfig.GetInt("APP_PORT")
 	err := app.Listen(fmt.Sprintf(":%d", webPort))
 	if err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+
+	stop()
+	workerRunner.Wait()
 }
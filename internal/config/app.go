@@ -1,11 +1,15 @@
 package config
 
 import (
+	"time"
+
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/gofiber/fiber/v2"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/delivery/http"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/delivery/http/middleware"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/delivery/http/route"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/delivery/worker"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
@@ -24,13 +28,17 @@ type BootstrapConfig struct {
 	Config      *viper.Viper
 	JWTMaker    *utils.JWTMaker
 	Cloudinary  *cloudinary.Cloudinary
-	Midtrans    *service.MidtransService
+	Payment     service.PaymentProvider
 	RedisClient *redis.Client
+	Worker      *worker.Runner
+	Location    *time.Location
 }
 
 func Bootstrap(config *BootstrapConfig) {
+	mailDispatcher := NewMailDispatcher(config.Config, config.Log, config.Location)
+
 	customerRepository := repository.NewCustomerRepository(config.Log)
-	customerUseCase := usecase.NewCustomerUseCase(config.DB, config.Log, config.Validator, customerRepository)
+	customerUseCase := usecase.NewCustomerUseCase(config.DB, config.Log, config.Validator, customerRepository, mailDispatcher)
 	customerController := http.NewCustomerController(customerUseCase, config.Log)
 
 	userRepository := repository.NewUserRepository(config.Log)
@@ -49,21 +57,98 @@ func Bootstrap(config *BootstrapConfig) {
 	productUseCase := usecase.NewProductUseCase(config.DB, config.Log, config.Validator, config.Cloudinary, productRepository)
 	productController := http.NewProductController(productUseCase, config.Log)
 
+	voucherRepository := repository.NewVoucherRepository(config.Log)
+	voucherUseCase := usecase.NewVoucherUseCase(config.DB, config.Log, config.Validator, voucherRepository, productRepository, categoryRepository)
+	voucherController := http.NewVoucherController(voucherUseCase, config.Log)
+
+	shippingZoneRepository := repository.NewShippingZoneRepository(config.Log)
+	shippingZoneUseCase := usecase.NewShippingZoneUseCase(config.DB, config.Log, config.Validator, shippingZoneRepository)
+	shippingZoneController := http.NewShippingZoneController(shippingZoneUseCase, config.Log)
+
+	orderBroker := NewOrderBroker(config.Config, config.RedisClient, config.Log)
 	orderRepository := repository.NewOrderRepository(config.Log)
-	orderUseCase := usecase.NewOrderUseCase(config.DB, config.Log, config.Validator, orderRepository, config.Midtrans)
-	orderController := http.NewOrderController(orderUseCase, config.Log)
+	pickupSlotRepository := repository.NewPickupSlotRepository(config.Log)
+	pickupSlotUseCase := usecase.NewPickupSlotUseCase(config.DB, config.Log, config.Validator, pickupSlotRepository, orderRepository, config.Location)
+	pickupSlotController := http.NewPickupSlotController(pickupSlotUseCase, config.Log)
+
+	paymentLogRepository := repository.NewPaymentLogRepository(config.Log)
+	orderHistoryRepository := repository.NewOrderStatusHistoryRepository(config.Log)
+	refundRepository := repository.NewRefundRepository(config.Log)
+	invoiceCounterRepository := repository.NewInvoiceCounterRepository(config.Log)
+	walletRepository := repository.NewWalletRepository(config.Log)
+	loyaltyRepository := repository.NewLoyaltyRepository(config.Log)
+	loyaltyProgram := NewLoyaltyProgram(config.Config, config.Log)
+	invoiceFormat := NewInvoiceFormat(config.Config, config.Location)
+	checkoutMode := NewCheckoutMode(config.Config, config.Log)
+	orderSchedule := NewOrderSchedule(config.Config, config.Log, config.Location)
+	orderUseCase := usecase.NewOrderUseCase(config.DB, config.Log, config.Validator, orderRepository, paymentLogRepository,
+		orderHistoryRepository, productRepository, refundRepository, voucherRepository, shippingZoneRepository, pickupSlotRepository,
+		customerRepository, walletRepository, loyaltyRepository, invoiceCounterRepository, invoiceFormat, config.Payment, checkoutMode,
+		NewPricingEngine(config.Config, config.Log), loyaltyProgram, orderSchedule, config.Location, mailDispatcher, orderBroker)
+
+	topUpFormat := NewTopUpInvoiceFormat(config.Config, invoiceFormat)
+	minTopUp, maxTopUp := NewWalletTopUpLimit(config.Config, config.Log, invoiceFormat, topUpFormat)
+	walletUseCase := usecase.NewWalletUseCase(config.DB, config.Log, config.Validator, walletRepository, customerRepository,
+		invoiceCounterRepository, topUpFormat, orderUseCase, config.Payment, checkoutMode, minTopUp, maxTopUp)
+	walletController := http.NewWalletController(walletUseCase, config.Log)
+
+	loyaltyUseCase := usecase.NewLoyaltyUseCase(config.DB, config.Log, config.Validator, loyaltyRepository, customerRepository, loyaltyProgram)
+	loyaltyController := http.NewLoyaltyController(loyaltyUseCase, config.Log)
+
+	orderController := http.NewOrderController(orderUseCase, walletUseCase, config.Log)
+
+	orderTrackingUseCase := usecase.NewOrderTrackingUseCase(config.DB, config.Log, config.Validator, orderRepository, orderBroker)
+	orderTrackingController := http.NewOrderTrackingController(orderTrackingUseCase, config.Log)
+
+	kitchenUseCase := usecase.NewKitchenUseCase(config.DB, config.Log, config.Validator, orderRepository, orderUseCase, orderBroker)
+	kitchenController := http.NewKitchenController(kitchenUseCase, config.Log)
+
+	orderDocumentUseCase := usecase.NewOrderDocumentUseCase(config.DB, config.Log, orderRepository,
+		NewStoreInfo(config.Config), config.Location)
+	orderDocumentController := http.NewOrderDocumentController(orderDocumentUseCase, config.Log)
+
+	reconciliationRepository := repository.NewReconciliationRepository(config.Log)
+	reconciliationUseCase := usecase.NewReconciliationUseCase(config.DB, config.Log, config.Validator, reconciliationRepository,
+		orderRepository, paymentLogRepository, walletRepository, orderUseCase, walletUseCase, config.Payment, config.Location)
+	reconciliationController := http.NewReconciliationController(reconciliationUseCase, config.Log)
 
 	authMiddleware := middleware.AuthMiddleware(config.JWTMaker)
+	idempotencyMiddleware := middleware.IdempotencyMiddleware(config.RedisClient, config.Log, config.Config.GetDuration("IDEMPOTENCY_TTL"))
+	// layar barista hanya untuk staff toko, bukan token customer
+	kitchenMiddleware := middleware.RoleMiddleware(string(entity.RoleAdmin), string(entity.RoleUser))
+	staffMiddleware := middleware.RoleMiddleware(string(entity.RoleAdmin), string(entity.RoleUser), string(entity.RoleFinance))
+	// cancel & refund mengembalikan uang, cukup admin & finance
+	financeMiddleware := middleware.RoleMiddleware(string(entity.RoleAdmin), string(entity.RoleFinance))
+
+	config.Worker.Register(mailDispatcher)
+	config.Worker.Register(orderBroker)
+	config.Worker.Register(worker.NewOrderExpiryWorker(orderUseCase, walletUseCase, config.Log, config.Config.GetDuration("ORDER_EXPIRY_INTERVAL")))
+	config.Worker.Register(worker.NewScheduledOrderWorker(orderUseCase, config.Log, config.Config.GetDuration("SCHEDULE_RELEASE_INTERVAL")))
+	config.Worker.Register(worker.NewLoyaltyExpiryWorker(loyaltyUseCase, config.Log, config.Config.GetDuration("LOYALTY_EXPIRY_INTERVAL")))
+	config.Worker.Register(worker.NewReconciliationWorker(reconciliationUseCase, config.RedisClient, config.Log, config.Location, config.Config.GetInt("RECONCILIATION_HOUR")))
 
 	routeConfig := route.RouteConfig{
-		App:                config.App,
-		AuthController:     authController,
-		AuthMiddleware:     authMiddleware,
-		CustomerController: customerController,
-		UserController:     userController,
-		CategoryController: categoryController,
-		ProductController:  productController,
-		OrderController:    orderController,
+		App:                      config.App,
+		AuthController:           authController,
+		AuthMiddleware:           authMiddleware,
+		IdempotencyMiddleware:    idempotencyMiddleware,
+		KitchenMiddleware:        kitchenMiddleware,
+		StaffMiddleware:          staffMiddleware,
+		FinanceMiddleware:        financeMiddleware,
+		CustomerController:       customerController,
+		UserController:           userController,
+		CategoryController:       categoryController,
+		ProductController:        productController,
+		VoucherController:        voucherController,
+		ShippingZoneController:   shippingZoneController,
+		PickupSlotController:     pickupSlotController,
+		WalletController:         walletController,
+		LoyaltyController:        loyaltyController,
+		OrderController:          orderController,
+		OrderTrackingController:  orderTrackingController,
+		KitchenController:        kitchenController,
+		OrderDocumentController:  orderDocumentController,
+		ReconciliationController: reconciliationController,
 	}
 	routeConfig.Setup()
 }
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/spf13/viper"
+)
+
+func NewInvoiceFormat(config *viper.Viper, location *time.Location) *utils.InvoiceFormat {
+	format := &utils.InvoiceFormat{
+		Prefix:     config.GetString("INVOICE_PREFIX"),
+		DateFormat: config.GetString("INVOICE_DATE_FORMAT"),
+		Digits:     config.GetInt("INVOICE_SEQ_DIGITS"),
+		Location:   location,
+	}
+
+	if format.Prefix == "" {
+		format.Prefix = "INV"
+	}
+	if format.DateFormat == "" {
+		format.DateFormat = "20060102"
+	}
+	if format.Digits <= 0 {
+		format.Digits = 4
+	}
+
+	return format
+}
+
+// NewTopUpInvoiceFormat format nomor top up wallet, sama dengan invoice order tapi prefix beda (default TOP)
+func NewTopUpInvoiceFormat(config *viper.Viper, invoiceFormat *utils.InvoiceFormat) *utils.InvoiceFormat {
+	format := *invoiceFormat
+	format.Prefix = config.GetString("WALLET_TOPUP_PREFIX")
+	if format.Prefix == "" {
+		format.Prefix = "TOP"
+	}
+	return &format
+}
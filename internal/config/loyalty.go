@@ -0,0 +1,45 @@
+package config
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewLoyaltyProgram(config *viper.Viper, log *logrus.Logger) *service.LoyaltyProgram {
+	program := &service.LoyaltyProgram{
+		EarnUnit:    config.GetInt64("LOYALTY_EARN_UNIT"),
+		Multipliers: make(map[string]int64),
+		PointValue:  config.GetInt64("LOYALTY_POINT_VALUE"),
+		MinRedeem:   config.GetInt64("LOYALTY_MIN_REDEEM"),
+		Expiry:      config.GetDuration("LOYALTY_POINT_EXPIRY"),
+	}
+
+	if program.EarnUnit < 0 || program.PointValue < 0 || program.MinRedeem < 0 {
+		log.Fatalf("loyalty config must not be negative: earn_unit=%d point_value=%d min_redeem=%d",
+			program.EarnUnit, program.PointValue, program.MinRedeem)
+	}
+	if program.PointValue == 0 {
+		program.PointValue = 1
+	}
+
+	// format: slug=multiplier dipisah koma, ex: kopi=2,merchandise=0
+	for _, pair := range strings.Split(config.GetString("LOYALTY_CATEGORY_MULTIPLIERS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		slug, value, ok := strings.Cut(pair, "=")
+		multiplier, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil || multiplier < 0 {
+			log.Fatalf("invalid LOYALTY_CATEGORY_MULTIPLIERS entry: %s", pair)
+		}
+		program.Multipliers[strings.TrimSpace(slug)] = int64(math.Round(multiplier * 10000))
+	}
+
+	return program
+}
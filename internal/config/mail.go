@@ -0,0 +1,34 @@
+package config
+
+import (
+	"net/mail"
+	"time"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/notification"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewMailer(config *viper.Viper, log *logrus.Logger) notification.Mailer {
+	switch driver := config.GetString("MAIL_DRIVER"); driver {
+	case "", notification.MailDriverLog:
+		return notification.NewLogMailer(log, config.GetString("MAIL_LOG_DIR"))
+	case notification.MailDriverSMTP:
+		return notification.NewSMTPMailer(config.GetString("SMTP_HOST"), config.GetInt("SMTP_PORT"),
+			config.GetString("SMTP_USERNAME"), config.GetString("SMTP_PASSWORD"))
+	default:
+		log.Fatalf("unknown mail driver: %s", driver)
+		return nil
+	}
+}
+
+func NewMailDispatcher(config *viper.Viper, log *logrus.Logger, location *time.Location) *notification.Dispatcher {
+	store := NewStoreInfo(config)
+	from := &mail.Address{Name: config.GetString("MAIL_FROM_NAME"), Address: config.GetString("MAIL_FROM")}
+	if from.Name == "" {
+		from.Name = store.Name
+	}
+
+	return notification.NewDispatcher(NewMailer(config, log), notification.NewTemplates(), from.String(), store.Name,
+		location, log, config.GetInt("MAIL_QUEUE_SIZE"))
+}
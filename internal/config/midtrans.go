@@ -3,16 +3,21 @@ package config
 import (
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
 	"github.com/spf13/viper"
 )
 
+func midtransEnv(config *viper.Viper) midtrans.EnvironmentType {
+	if config.GetBool("MIDTRANS_IS_PRODUCTION") {
+		return midtrans.Production
+	}
+	return midtrans.Sandbox
+}
+
 func NewMidtransClient(config *viper.Viper) *coreapi.Client {
 	var client coreapi.Client
 
-	env := midtrans.Sandbox
-	if config.GetBool("MIDTRANS_IS_PRODUCTION") {
-		env = midtrans.Production
-	}
+	env := midtransEnv(config)
 
 	client.ClientKey = config.GetString("MIDTRANS_CLIENT_KEY")
 
@@ -23,3 +28,9 @@ func NewMidtransClient(config *viper.Viper) *coreapi.Client {
 
 	return &client
 }
+
+func NewMidtransSnapClient(config *viper.Viper) *snap.Client {
+	var client snap.Client
+	client.New(config.GetString("MIDTRANS_SERVER_KEY"), midtransEnv(config))
+	return &client
+}
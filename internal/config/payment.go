@@ -0,0 +1,34 @@
+package config
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewCheckoutMode mode checkout default kalau request tidak memilih sendiri
+func NewCheckoutMode(config *viper.Viper, log *logrus.Logger) string {
+	switch mode := config.GetString("PAYMENT_CHECKOUT_MODE"); mode {
+	case "":
+		return entity.CheckoutModeCore
+	case entity.CheckoutModeCore, entity.CheckoutModeSnap:
+		return mode
+	default:
+		log.Fatalf("unknown payment checkout mode: %s", mode)
+		return ""
+	}
+}
+
+func NewPaymentProvider(config *viper.Viper, log *logrus.Logger) service.PaymentProvider {
+	switch provider := config.GetString("PAYMENT_PROVIDER"); provider {
+	case "", service.PaymentProviderMidtrans:
+		return service.NewMidtransService(NewMidtransClient(config), NewMidtransSnapClient(config))
+	case service.PaymentProviderFake:
+		log.Warn("Using fake payment provider, do not use this in production")
+		return service.NewFakePaymentService(config.GetString("MIDTRANS_SERVER_KEY"))
+	default:
+		log.Fatalf("unknown payment provider: %s", provider)
+		return nil
+	}
+}
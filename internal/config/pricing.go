@@ -0,0 +1,36 @@
+package config
+
+import (
+	"math"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewPricingEngine(config *viper.Viper, log *logrus.Logger) *service.PricingEngine {
+	taxRate := config.GetFloat64("PRICING_TAX_RATE")
+	serviceRate := config.GetFloat64("PRICING_SERVICE_RATE")
+	if taxRate < 0 || serviceRate < 0 {
+		log.Fatalf("pricing rate must not be negative: tax=%v service=%v", taxRate, serviceRate)
+	}
+
+	engine := &service.PricingEngine{
+		TaxRate:      int64(math.Round(taxRate * 100)), // persen → basis point
+		ServiceRate:  int64(math.Round(serviceRate * 100)),
+		TaxInclusive: config.GetBool("PRICING_TAX_INCLUSIVE"),
+		TaxOnService: config.GetBool("PRICING_TAX_ON_SERVICE"),
+		Rounding:     config.GetString("PRICING_ROUNDING"),
+		RoundingUnit: config.GetInt64("PRICING_ROUNDING_UNIT"),
+	}
+
+	switch engine.Rounding {
+	case "":
+		engine.Rounding = service.RoundingNone
+	case service.RoundingNone, service.RoundingUp, service.RoundingDown, service.RoundingNearest:
+	default:
+		log.Fatalf("unknown pricing rounding: %s", engine.Rounding)
+	}
+
+	return engine
+}
@@ -0,0 +1,14 @@
+package config
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/realtime"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewOrderBroker semua instance API harus pakai REALTIME_ORDER_CHANNEL yang sama supaya event sampai ke semua layar
+func NewOrderBroker(config *viper.Viper, redisClient *redis.Client, log *logrus.Logger) *realtime.OrderBroker {
+	return realtime.NewOrderBroker(redisClient, config.GetString("REALTIME_ORDER_CHANNEL"),
+		config.GetDuration("REALTIME_REPLAY_TTL"), log)
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"time"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+func NewOrderSchedule(config *viper.Viper, log *logrus.Logger, location *time.Location) *service.OrderSchedule {
+	schedule := &service.OrderSchedule{
+		OpenTime:   config.GetString("STORE_OPEN_TIME"),
+		CloseTime:  config.GetString("STORE_CLOSE_TIME"),
+		Location:   location,
+		LeadTime:   config.GetDuration("SCHEDULE_LEAD_TIME"),
+		MaxDays:    config.GetInt("SCHEDULE_MAX_DAYS"),
+		PrepWindow: config.GetDuration("SCHEDULE_PREP_WINDOW"),
+	}
+
+	if schedule.OpenTime == "" {
+		schedule.OpenTime = "07:00"
+	}
+	if schedule.CloseTime == "" {
+		schedule.CloseTime = "21:00"
+	}
+	openAt, errOpen := time.Parse("15:04", schedule.OpenTime)
+	closeAt, errClose := time.Parse("15:04", schedule.CloseTime)
+	if errOpen != nil || errClose != nil || !openAt.Before(closeAt) {
+		log.Fatalf("invalid store hours: open=%s close=%s", schedule.OpenTime, schedule.CloseTime)
+	}
+	// simpan dalam format HH:MM (ex: "7:00" -> "07:00"), Check membandingkan jam sebagai string
+	schedule.OpenTime = openAt.Format("15:04")
+	schedule.CloseTime = closeAt.Format("15:04")
+
+	if schedule.LeadTime <= 0 {
+		schedule.LeadTime = 30 * time.Minute
+	}
+	if schedule.MaxDays <= 0 {
+		schedule.MaxDays = 7
+	}
+	if schedule.PrepWindow <= 0 {
+		schedule.PrepWindow = 15 * time.Minute
+	}
+
+	return schedule
+}
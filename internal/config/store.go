@@ -0,0 +1,40 @@
+package config
+
+import (
+	"time"
+	_ "time/tzdata" // supaya tetap jalan di image tanpa zoneinfo (ex: alpine, scratch)
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewStoreLocation timezone toko, dipakai untuk batas hari (invoice, laporan, jam buka)
+func NewStoreLocation(config *viper.Viper, log *logrus.Logger) *time.Location {
+	name := config.GetString("STORE_TIMEZONE")
+	if name == "" {
+		name = "Asia/Jakarta"
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		log.Fatalf("invalid store timezone %s: %v", name, err)
+	}
+	return location
+}
+
+// NewStoreInfo identitas toko untuk invoice & receipt
+func NewStoreInfo(config *viper.Viper) *utils.StoreInfo {
+	name := config.GetString("STORE_NAME")
+	if name == "" {
+		name = "Daily Coffee"
+	}
+
+	return &utils.StoreInfo{
+		Name:    name,
+		Address: config.GetString("STORE_ADDRESS"),
+		Phone:   config.GetString("STORE_PHONE"),
+		Email:   config.GetString("STORE_EMAIL"),
+		TaxID:   config.GetString("STORE_TAX_ID"),
+	}
+}
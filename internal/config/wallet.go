@@ -0,0 +1,26 @@
+package config
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+)
+
+// NewWalletTopUpLimit batas nominal sekali top up, max 0 = tanpa batas
+func NewWalletTopUpLimit(config *viper.Viper, log *logrus.Logger, invoiceFormat, topUpFormat *utils.InvoiceFormat) (int64, int64) {
+	// prefix sama bikin notifikasi Midtrans order & top up tidak bisa dibedakan
+	if topUpFormat.Prefix == invoiceFormat.Prefix {
+		log.Fatalf("WALLET_TOPUP_PREFIX must be different from INVOICE_PREFIX: %s", topUpFormat.Prefix)
+	}
+
+	minTopUp := config.GetInt64("WALLET_TOPUP_MIN")
+	maxTopUp := config.GetInt64("WALLET_TOPUP_MAX")
+	if minTopUp <= 0 {
+		minTopUp = 10000
+	}
+	if maxTopUp != 0 && maxTopUp < minTopUp {
+		log.Fatalf("WALLET_TOPUP_MAX must not be lower than WALLET_TOPUP_MIN: min=%d max=%d", minTopUp, maxTopUp)
+	}
+
+	return minTopUp, maxTopUp
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"bufio"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type KitchenController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.KitchenUseCase
+}
+
+func NewKitchenController(useCase *usecase.KitchenUseCase, logger *logrus.Logger) *KitchenController {
+	return &KitchenController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *KitchenController) FindQueue(ctx *fiber.Ctx) error {
+	queue, err := c.UseCase.FindQueue(ctx.Context())
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponse(fiber.StatusOK, "get kitchen queue successfully", queue))
+}
+
+func (c *KitchenController) Move(ctx *fiber.Ctx) error {
+	request := new(model.MoveKitchenOrderRequest)
+	id := ctx.Params("id")
+	userID, _ := ctx.Locals("userID").(string)
+
+	err := ctx.BodyParser(request)
+	if err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
+	}
+
+	err = c.UseCase.Move(ctx.UserContext(), id, userID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to move kitchen order : %+v", err)
+
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrNotFound):
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "order not found"))
+
+		case errors.Is(err, utils.ErrConflict), errors.Is(err, utils.ErrInvalidStatusTransition):
+			return ctx.Status(fiber.StatusConflict).
+				JSON(utils.ErrorResponse(fiber.StatusConflict, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.DefaultSuccessResponse(fiber.StatusOK, "move kitchen order successfully"))
+}
+
+// Stream Server-Sent Events untuk layar barista. Event pertama "snapshot" berisi seluruh antrian,
+// setelah itu event "order" setiap ada order yang masuk, pindah, atau keluar dari antrian.
+func (c *KitchenController) Stream(ctx *fiber.Ctx) error {
+	// subscribe dulu sebelum ambil snapshot, supaya perubahan di antaranya tidak hilang
+	subscription := c.UseCase.Subscribe()
+
+	queue, err := c.UseCase.FindQueue(ctx.Context())
+	if err != nil {
+		subscription.Close()
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+	}
+
+	setSSEHeaders(ctx)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer subscription.Close()
+
+		keepAlive := time.NewTicker(sseKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		if err := writeSSE(w, "", "snapshot", queue); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-subscription.Events():
+				if !ok {
+					return
+				}
+				update := c.UseCase.ToKitchenEvent(event)
+				if update == nil {
+					continue
+				}
+				if err := writeSSE(w, "", "order", update); err != nil {
+					return
+				}
+			case <-keepAlive.C:
+				if err := writeSSEKeepAlive(w); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
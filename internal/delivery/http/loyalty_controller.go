@@ -0,0 +1,147 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type LoyaltyController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.LoyaltyUseCase
+}
+
+func NewLoyaltyController(useCase *usecase.LoyaltyUseCase, logger *logrus.Logger) *LoyaltyController {
+	return &LoyaltyController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *LoyaltyController) FindForCustomer(ctx *fiber.Ctx) error {
+	customerID, _ := ctx.Locals("userID").(string)
+
+	account, err := c.UseCase.FindByCustomerID(ctx.Context(), customerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrUnauthorized):
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponse(fiber.StatusOK, "get loyalty points successfully", account))
+}
+
+func (c *LoyaltyController) FindTransactionsForCustomer(ctx *fiber.Ctx) error {
+	customerID, _ := ctx.Locals("userID").(string)
+
+	req := &utils.PaginationRequest{
+		Page:  ctx.QueryInt("page", 1),
+		Limit: ctx.QueryInt("limit", 10),
+	}
+
+	transactions, pagination, err := c.UseCase.FindTransactions(ctx.Context(), customerID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrUnauthorized):
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponseWithPagination(fiber.StatusOK, "get list loyalty transaction successfully", transactions, pagination))
+}
+
+func (c *LoyaltyController) FindByCustomerID(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+
+	account, err := c.UseCase.FindByCustomerIDForCMS(ctx.Context(), id)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "customer not found"))
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponse(fiber.StatusOK, "get loyalty points successfully", account))
+}
+
+func (c *LoyaltyController) FindTransactions(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+
+	req := &utils.PaginationRequest{
+		Page:  ctx.QueryInt("page", 1),
+		Limit: ctx.QueryInt("limit", 10),
+	}
+
+	transactions, pagination, err := c.UseCase.FindTransactionsForCMS(ctx.Context(), id, req)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "customer not found"))
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponseWithPagination(fiber.StatusOK, "get list loyalty transaction successfully", transactions, pagination))
+}
+
+func (c *LoyaltyController) Adjust(ctx *fiber.Ctx) error {
+	request := new(model.AdjustLoyaltyRequest)
+	id := ctx.Params("id")
+	userID, _ := ctx.Locals("userID").(string)
+
+	err := ctx.BodyParser(request)
+	if err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
+	}
+
+	response, err := c.UseCase.Adjust(ctx.Context(), id, userID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to adjust loyalty points : %+v", err)
+
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrNotFound):
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "customer not found"))
+
+		case errors.Is(err, utils.ErrInsufficientBalance):
+			return ctx.Status(fiber.StatusConflict).
+				JSON(utils.ErrorResponse(fiber.StatusConflict, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(utils.SuccessResponse(fiber.StatusCreated, "adjust loyalty points successfully", response))
+}
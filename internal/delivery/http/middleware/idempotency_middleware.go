@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	IdempotencyHeader = "Idempotency-Key"
+
+	idempotencyStatusProcessing = "processing"
+	idempotencyStatusDone       = "done"
+
+	// lock sementara selama request pertama jalan, supaya key tidak nyangkut kalau instance mati.
+	// Selama handler masih jalan TTL-nya diperpanjang tiap idempotencyLockRefresh, jadi request lambat
+	// (ex: charge Midtrans) tidak kehilangan lock dan retry tidak menjalankan handler dua kali.
+	idempotencyLockTTL     = time.Minute
+	idempotencyLockRefresh = idempotencyLockTTL / 3
+	// request kedua menunggu request pertama selesai maksimal segini, setelah itu dapat 409 in progress
+	idempotencyWait         = 10 * time.Second
+	idempotencyPollInterval = 200 * time.Millisecond
+)
+
+type idempotencyRecord struct {
+	Status      string `json:"status"`
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// IdempotencyMiddleware simpan response pertama untuk tiap Idempotency-Key di Redis.
+// Retry dengan key & body yang sama dapat response yang sama tanpa menjalankan handler lagi.
+// Header tidak wajib, request tanpa key diproses seperti biasa. Untuk route yang butuh login
+// middleware ini dipasang setelah AuthMiddleware supaya key ter-scope per user.
+func IdempotencyMiddleware(redisClient *redis.Client, log *logrus.Logger, ttl time.Duration) fiber.Handler {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyHeader)
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > 255 {
+			return c.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Idempotency-Key is too long"))
+		}
+
+		// key di-scope per pemanggil, jadi key yang sama dari user lain tidak bisa membaca response orang lain
+		scope := "guest"
+		if userID, ok := c.Locals("userID").(string); ok && userID != "" {
+			scope = userID
+		}
+		redisKey := "idempotency:" + scope + ":" + c.Method() + ":" + c.Path() + ":" + key
+		sum := sha256.Sum256(c.Body())
+		fingerprint := hex.EncodeToString(sum[:])
+
+		processing, _ := json.Marshal(idempotencyRecord{
+			Status:      idempotencyStatusProcessing,
+			Fingerprint: fingerprint,
+		})
+
+		deadline := time.Now().Add(idempotencyWait)
+		for {
+			acquired, err := redisClient.SetNX(c.Context(), redisKey, processing, idempotencyLockTTL).Result()
+			if err != nil {
+				log.Warnf("Failed acquire idempotency key : %+v", err)
+				return c.Status(fiber.StatusServiceUnavailable).
+					JSON(utils.ErrorResponse(fiber.StatusServiceUnavailable, "service unavailable"))
+			}
+			if acquired {
+				return runIdempotent(c, redisClient, log, redisKey, fingerprint, ttl)
+			}
+
+			val, err := redisClient.Get(c.Context(), redisKey).Bytes()
+			if errors.Is(err, redis.Nil) {
+				// key baru saja dihapus / expired, coba ambil lagi
+				continue
+			}
+			if err != nil {
+				log.Warnf("Failed get idempotency key : %+v", err)
+				return c.Status(fiber.StatusServiceUnavailable).
+					JSON(utils.ErrorResponse(fiber.StatusServiceUnavailable, "service unavailable"))
+			}
+
+			var record idempotencyRecord
+			if err := json.Unmarshal(val, &record); err != nil {
+				log.Warnf("Invalid idempotency record, key=%s : %+v", key, err)
+				return c.Status(fiber.StatusInternalServerError).
+					JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+			}
+
+			if record.Fingerprint != fingerprint {
+				return c.Status(fiber.StatusConflict).
+					JSON(utils.ErrorResponse(fiber.StatusConflict, "Idempotency-Key was already used with a different request"))
+			}
+
+			if record.Status == idempotencyStatusDone {
+				c.Set("Idempotent-Replayed", "true")
+				if record.ContentType != "" {
+					c.Set(fiber.HeaderContentType, record.ContentType)
+				}
+				return c.Status(record.StatusCode).Send(record.Body)
+			}
+
+			if time.Now().After(deadline) {
+				c.Set(fiber.HeaderRetryAfter, "1")
+				return c.Status(fiber.StatusConflict).
+					JSON(utils.ErrorResponse(fiber.StatusConflict, "request with this Idempotency-Key is still in progress"))
+			}
+			time.Sleep(idempotencyPollInterval)
+		}
+	}
+}
+
+func runIdempotent(c *fiber.Ctx, redisClient *redis.Client, log *logrus.Logger, redisKey, fingerprint string, ttl time.Duration) error {
+	stop := keepIdempotencyLock(redisClient, log, redisKey)
+	defer stop() // kalau handler panic
+	err := c.Next()
+	stop()
+
+	// error server tidak disimpan, client boleh retry dengan key yang sama
+	statusCode := c.Response().StatusCode()
+	if err != nil || statusCode >= fiber.StatusInternalServerError {
+		if delErr := redisClient.Del(c.Context(), redisKey).Err(); delErr != nil {
+			log.Warnf("Failed delete idempotency key : %+v", delErr)
+		}
+		return err
+	}
+
+	record, _ := json.Marshal(idempotencyRecord{
+		Status:      idempotencyStatusDone,
+		Fingerprint: fingerprint,
+		StatusCode:  statusCode,
+		ContentType: string(c.Response().Header.ContentType()),
+		Body:        c.Response().Body(),
+	})
+	if err := redisClient.Set(c.Context(), redisKey, record, ttl).Err(); err != nil {
+		log.Warnf("Failed save idempotency response : %+v", err)
+	}
+
+	return nil
+}
+
+// keepIdempotencyLock perpanjang lock processing sampai stop dipanggil. stop menunggu goroutine selesai,
+// jadi tidak ada Expire yang menimpa TTL record done setelahnya. Aman dipanggil lebih dari sekali.
+func keepIdempotencyLock(redisClient *redis.Client, log *logrus.Logger, redisKey string) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+
+		ticker := time.NewTicker(idempotencyLockRefresh)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// fasthttp ctx tidak aman dipakai di goroutine lain
+				ctx, cancel := context.WithTimeout(context.Background(), idempotencyLockRefresh)
+				err := redisClient.Expire(ctx, redisKey, idempotencyLockTTL).Err()
+				cancel()
+				if err != nil {
+					log.Warnf("Failed refresh idempotency lock : %+v", err)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+		})
+	}
+}
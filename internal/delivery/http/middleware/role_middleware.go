@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+)
+
+// RoleMiddleware dipasang setelah AuthMiddleware, hanya role yang terdaftar yang boleh lanjut
+func RoleMiddleware(roles ...string) fiber.Handler {
+	allowed := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		allowed[role] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("role").(string)
+		if !allowed[role] {
+			return c.Status(fiber.StatusForbidden).
+				JSON(utils.ErrorResponse(fiber.StatusForbidden, "forbidden"))
+		}
+
+		return c.Next()
+	}
+}
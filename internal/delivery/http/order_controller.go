@@ -13,12 +13,14 @@ import (
 type OrderController struct {
 	Log     *logrus.Logger
 	UseCase *usecase.OrderUseCase
+	Wallet  *usecase.WalletUseCase
 }
 
-func NewOrderController(useCase *usecase.OrderUseCase, logger *logrus.Logger) *OrderController {
+func NewOrderController(useCase *usecase.OrderUseCase, wallet *usecase.WalletUseCase, logger *logrus.Logger) *OrderController {
 	return &OrderController{
 		Log:     logger,
 		UseCase: useCase,
+		Wallet:  wallet,
 	}
 }
 
@@ -31,16 +33,209 @@ func (c *OrderController) Create(ctx *fiber.Ctx) error {
 		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
 	}
 
-	err = c.UseCase.Create(ctx.UserContext(), request)
+	response, err := c.UseCase.Create(ctx.UserContext(), request)
 	if err != nil {
-		c.Log.Warnf("Failed to register user : %+v", err)
+		return c.createError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(utils.SuccessResponse(fiber.StatusCreated, "order created successfully", response))
+}
+
+// CreateForCustomer checkout customer yang login, satu-satunya yang bisa bayar pakai wallet
+func (c *OrderController) CreateForCustomer(ctx *fiber.Ctx) error {
+	request := new(model.CreateOrderRequest)
+	customerID, _ := ctx.Locals("userID").(string)
+
+	err := ctx.BodyParser(request)
+	if err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
+	}
+
+	response, err := c.UseCase.CreateForCustomer(ctx.UserContext(), customerID, request)
+	if err != nil {
+		return c.createError(ctx, err)
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(utils.SuccessResponse(fiber.StatusCreated, "order created successfully", response))
+}
+
+func (c *OrderController) createError(ctx *fiber.Ctx, err error) error {
+	c.Log.Warnf("Failed to create order : %+v", err)
+
+	var priceChanged *utils.PriceChangedError
+	var outOfStock *utils.OutOfStockError
+	switch {
+	case errors.As(err, &priceChanged):
+		return ctx.Status(fiber.StatusConflict).
+			JSON(utils.ErrorResponseWithData(fiber.StatusConflict, utils.ErrPriceChanged.Error(), priceChanged.Items))
+
+	case errors.As(err, &outOfStock):
+		return ctx.Status(fiber.StatusConflict).
+			JSON(utils.ErrorResponseWithData(fiber.StatusConflict, outOfStock.Error(), outOfStock.Items))
+
+	case errors.Is(err, utils.ErrValidation):
+		return ctx.Status(fiber.StatusBadRequest).
+			JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+	case errors.Is(err, utils.ErrUnauthorized):
+		return ctx.Status(fiber.StatusUnauthorized).
+			JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+	case errors.Is(err, utils.ErrInsufficientBalance):
+		return ctx.Status(fiber.StatusPaymentRequired).
+			JSON(utils.ErrorResponse(fiber.StatusPaymentRequired, err.Error()))
+
+	case errors.Is(err, utils.ErrConflict):
+		return ctx.Status(fiber.StatusConflict).
+			JSON(utils.ErrorResponse(fiber.StatusConflict, err.Error()))
+
+	default: // internal error
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+	}
+}
+
+func (c *OrderController) Notification(ctx *fiber.Ctx) error {
+	request := new(model.MidtransNotificationRequest)
+
+	err := ctx.BodyParser(request)
+	if err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
+	}
+
+	// satu endpoint notifikasi Midtrans untuk order & top up wallet, dibedakan dari prefix order_id
+	if c.Wallet.IsTopUpInvoice(request.OrderID) {
+		err = c.Wallet.HandleTopUpNotification(ctx.UserContext(), request)
+	} else {
+		err = c.UseCase.HandleNotification(ctx.UserContext(), request, ctx.Body())
+	}
+	if err != nil {
+		c.Log.Warnf("Failed to handle payment notification : %+v", err)
+
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrInvalidSignature):
+			return ctx.Status(fiber.StatusForbidden).
+				JSON(utils.ErrorResponse(fiber.StatusForbidden, err.Error()))
+
+		case errors.Is(err, utils.ErrNotFound):
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "order not found"))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.DefaultSuccessResponse(fiber.StatusOK, "notification processed successfully"))
+}
+
+func (c *OrderController) FindAll(ctx *fiber.Ctx) error {
+	req := &utils.PaginationRequest{
+		Page:    ctx.QueryInt("page", 1),
+		Limit:   ctx.QueryInt("limit", 10),
+		OrderBy: ctx.Query("order_by", "created_at"),
+		SortBy:  ctx.Query("sort_by", "desc"),
+		Search:  ctx.Query("search", ""),
+	}
+
+	filter := &model.SearchOrderRequest{
+		Status:          ctx.Query("status", ""),
+		PaymentType:     ctx.Query("payment_type", ""),
+		CustomerID:      ctx.Query("customer_id", ""),
+		FulfillmentType: ctx.Query("fulfillment_type", ""),
+		DateFrom:        ctx.Query("date_from", ""),
+		DateTo:          ctx.Query("date_to", ""),
+	}
+
+	orders, pagination, err := c.UseCase.FindAll(ctx.Context(), req, filter)
+	if err != nil {
+		if errors.Is(err, utils.ErrValidation) {
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponseWithPagination(fiber.StatusOK, "get list order successfully", orders, pagination))
+}
+
+func (c *OrderController) FindByID(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+
+	order, err := c.UseCase.FindByID(ctx.Context(), id)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "order not found"))
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponse(fiber.StatusOK, "get detail order successfully", order))
+}
+
+func (c *OrderController) FindScheduled(ctx *fiber.Ctx) error {
+	request := &model.SearchScheduledOrderRequest{
+		DateFrom: ctx.Query("date_from", ""),
+		DateTo:   ctx.Query("date_to", ""),
+	}
+
+	days, err := c.UseCase.FindScheduled(ctx.Context(), request)
+	if err != nil {
+		if errors.Is(err, utils.ErrValidation) {
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponse(fiber.StatusOK, "get list scheduled order successfully", days))
+}
+
+func (c *OrderController) UpdateStatus(ctx *fiber.Ctx) error {
+	request := new(model.UpdateOrderStatusRequest)
+	id := ctx.Params("id")
+	userID, _ := ctx.Locals("userID").(string)
+
+	err := ctx.BodyParser(request)
+	if err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
+	}
+
+	err = c.UseCase.UpdateStatus(ctx.Context(), id, userID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to update order status : %+v", err)
 
 		switch {
 		case errors.Is(err, utils.ErrValidation):
 			return ctx.Status(fiber.StatusBadRequest).
 				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
 
-		case errors.Is(err, utils.ErrConflict):
+		case errors.Is(err, utils.ErrNotFound):
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "order not found"))
+
+		case errors.Is(err, utils.ErrConflict), errors.Is(err, utils.ErrInvalidStatusTransition):
 			return ctx.Status(fiber.StatusConflict).
 				JSON(utils.ErrorResponse(fiber.StatusConflict, err.Error()))
 
@@ -50,86 +245,155 @@ func (c *OrderController) Create(ctx *fiber.Ctx) error {
 		}
 	}
 
-	return ctx.Status(fiber.StatusCreated).
-		JSON(utils.DefaultSuccessResponse(fiber.StatusCreated, "order created successfully"))
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.DefaultSuccessResponse(fiber.StatusOK, "update order status successfully"))
+}
+
+func (c *OrderController) FindAllForCustomer(ctx *fiber.Ctx) error {
+	customerID, _ := ctx.Locals("userID").(string)
+
+	req := &utils.PaginationRequest{
+		Page:    ctx.QueryInt("page", 1),
+		Limit:   ctx.QueryInt("limit", 10),
+		OrderBy: ctx.Query("order_by", "created_at"),
+		SortBy:  ctx.Query("sort_by", "desc"),
+		Search:  ctx.Query("search", ""),
+	}
+
+	filter := &model.SearchOrderRequest{
+		Status:   ctx.Query("status", ""),
+		DateFrom: ctx.Query("date_from", ""),
+		DateTo:   ctx.Query("date_to", ""),
+	}
+
+	orders, pagination, err := c.UseCase.FindAllForCustomer(ctx.Context(), customerID, req, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrUnauthorized):
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponseWithPagination(fiber.StatusOK, "get list order successfully", orders, pagination))
 }
 
-// func (c *OrderController) FindAll(ctx *fiber.Ctx) error {
-// 	req := &utils.PaginationRequest{
-// 		Page:    ctx.QueryInt("page", 1),
-// 		Limit:   ctx.QueryInt("limit", 10),
-// 		OrderBy: ctx.Query("order_by", "created_at"),
-// 		SortBy:  ctx.Query("sort_by", "desc"),
-// 		Search:  ctx.Query("search", ""),
-// 	}
-
-// 	categories, pagination, err := c.UseCase.FindAll(ctx.Context(), req)
-// 	if err != nil {
-// 		return ctx.Status(fiber.StatusInternalServerError).
-// 			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, err.Error()))
-// 	}
-
-// 	return ctx.Status(fiber.StatusOK).
-// 		JSON(utils.SuccessResponseWithPagination(fiber.StatusOK, "get list user successfully", categories, pagination))
-// }
-
-// func (c *UserController) FindByID(ctx *fiber.Ctx) error {
-// 	id := ctx.Params("id")
-
-// 	user, err := c.UseCase.FindByID(ctx.Context(), id)
-// 	if err != nil {
-// 		if errors.Is(err, utils.ErrNotFound) {
-// 			return ctx.Status(fiber.StatusNotFound).
-// 				JSON(utils.ErrorResponse(fiber.StatusNotFound, "user not found"))
-// 		}
-
-// 		return ctx.Status(fiber.StatusInternalServerError).
-// 			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
-// 	}
-
-// 	return ctx.Status(fiber.StatusOK).
-// 		JSON(utils.SuccessResponse(fiber.StatusOK, "get detail user successfully", user))
-// }
-
-// func (c *UserController) Update(ctx *fiber.Ctx) error {
-// 	request := new(model.UpdateUserRequest)
-// 	id := ctx.Params("id")
-
-// 	err := ctx.BodyParser(request)
-// 	if err != nil {
-// 		c.Log.Warnf("Failed to parse request body : %+v", err)
-// 		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
-// 	}
-
-// 	err = c.UseCase.Update(ctx.Context(), id, request)
-// 	if err != nil {
-// 		if errors.Is(err, utils.ErrNotFound) {
-// 			return ctx.Status(fiber.StatusNotFound).
-// 				JSON(utils.ErrorResponse(fiber.StatusNotFound, "user not found"))
-// 		}
-
-// 		return ctx.Status(fiber.StatusInternalServerError).
-// 			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
-// 	}
-
-// 	return ctx.Status(fiber.StatusOK).
-// 		JSON(utils.DefaultSuccessResponse(fiber.StatusOK, "update user successfully"))
-// }
-
-// func (c *UserController) Delete(ctx *fiber.Ctx) error {
-// 	id := ctx.Params("id")
-
-// 	err := c.UseCase.Delete(ctx.Context(), id)
-// 	if err != nil {
-// 		if errors.Is(err, utils.ErrNotFound) {
-// 			return ctx.Status(fiber.StatusNotFound).
-// 				JSON(utils.ErrorResponse(fiber.StatusNotFound, "user not found"))
-// 		}
-
-// 		return ctx.Status(fiber.StatusInternalServerError).
-// 			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
-// 	}
-
-// 	return ctx.Status(fiber.StatusOK).
-// 		JSON(utils.DefaultSuccessResponse(fiber.StatusOK, "delete user successfully"))
-// }
+func (c *OrderController) FindByIDForCustomer(ctx *fiber.Ctx) error {
+	customerID, _ := ctx.Locals("userID").(string)
+	id := ctx.Params("id")
+
+	order, err := c.UseCase.FindByIDForCustomer(ctx.Context(), customerID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrNotFound):
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "order not found"))
+
+		case errors.Is(err, utils.ErrUnauthorized):
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponse(fiber.StatusOK, "get detail order successfully", order))
+}
+
+func (c *OrderController) Cancel(ctx *fiber.Ctx) error {
+	request := new(model.CancelOrderRequest)
+	id := ctx.Params("id")
+	userID, _ := ctx.Locals("userID").(string)
+
+	err := ctx.BodyParser(request)
+	if err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
+	}
+
+	err = c.UseCase.Cancel(ctx.Context(), id, userID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to cancel order : %+v", err)
+
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrNotFound):
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "order not found"))
+
+		case errors.Is(err, utils.ErrConflict), errors.Is(err, utils.ErrPaymentCancelled),
+			errors.Is(err, utils.ErrInvalidStatusTransition):
+			return ctx.Status(fiber.StatusConflict).
+				JSON(utils.ErrorResponse(fiber.StatusConflict, err.Error()))
+
+		case errors.Is(err, utils.ErrPayment):
+			return ctx.Status(fiber.StatusBadGateway).
+				JSON(utils.ErrorResponse(fiber.StatusBadGateway, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.DefaultSuccessResponse(fiber.StatusOK, "cancel order successfully"))
+}
+
+func (c *OrderController) Refund(ctx *fiber.Ctx) error {
+	request := new(model.RefundOrderRequest)
+	id := ctx.Params("id")
+	userID, _ := ctx.Locals("userID").(string)
+
+	err := ctx.BodyParser(request)
+	if err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
+	}
+
+	err = c.UseCase.Refund(ctx.Context(), id, userID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to refund order : %+v", err)
+
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrNotFound):
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "order not found"))
+
+		case errors.Is(err, utils.ErrConflict), errors.Is(err, utils.ErrPaymentCancelled),
+			errors.Is(err, utils.ErrInvalidStatusTransition):
+			return ctx.Status(fiber.StatusConflict).
+				JSON(utils.ErrorResponse(fiber.StatusConflict, err.Error()))
+
+		case errors.Is(err, utils.ErrPayment):
+			return ctx.Status(fiber.StatusBadGateway).
+				JSON(utils.ErrorResponse(fiber.StatusBadGateway, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.DefaultSuccessResponse(fiber.StatusOK, "refund order successfully"))
+}
@@ -0,0 +1,77 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type OrderDocumentController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.OrderDocumentUseCase
+}
+
+func NewOrderDocumentController(useCase *usecase.OrderDocumentUseCase, logger *logrus.Logger) *OrderDocumentController {
+	return &OrderDocumentController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *OrderDocumentController) Invoice(ctx *fiber.Ctx) error {
+	document, err := c.UseCase.Render(ctx.UserContext(), ctx.Params("id"), usecase.DocumentInvoice)
+	return c.send(ctx, document, err)
+}
+
+func (c *OrderDocumentController) Receipt(ctx *fiber.Ctx) error {
+	document, err := c.UseCase.Render(ctx.UserContext(), ctx.Params("id"), usecase.DocumentReceipt)
+	return c.send(ctx, document, err)
+}
+
+func (c *OrderDocumentController) CustomerInvoice(ctx *fiber.Ctx) error {
+	customerID, _ := ctx.Locals("userID").(string)
+	document, err := c.UseCase.RenderForCustomer(ctx.UserContext(), customerID, ctx.Params("id"), usecase.DocumentInvoice)
+	return c.send(ctx, document, err)
+}
+
+func (c *OrderDocumentController) CustomerReceipt(ctx *fiber.Ctx) error {
+	customerID, _ := ctx.Locals("userID").(string)
+	document, err := c.UseCase.RenderForCustomer(ctx.UserContext(), customerID, ctx.Params("id"), usecase.DocumentReceipt)
+	return c.send(ctx, document, err)
+}
+
+func (c *OrderDocumentController) send(ctx *fiber.Ctx, document *model.OrderDocumentResponse, err error) error {
+	if err != nil {
+		c.Log.Warnf("Failed to render order document : %+v", err)
+
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrNotFound):
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "order not found"))
+
+		case errors.Is(err, utils.ErrUnauthorized):
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+		case errors.Is(err, utils.ErrConflict):
+			return ctx.Status(fiber.StatusConflict).
+				JSON(utils.ErrorResponse(fiber.StatusConflict, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	ctx.Set(fiber.HeaderContentType, "application/pdf")
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="`+document.FileName+`"`)
+	return ctx.Status(fiber.StatusOK).Send(document.Content)
+}
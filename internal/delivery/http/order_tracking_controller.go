@@ -0,0 +1,103 @@
+package http
+
+import (
+	"bufio"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type OrderTrackingController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.OrderTrackingUseCase
+}
+
+func NewOrderTrackingController(useCase *usecase.OrderTrackingUseCase, logger *logrus.Logger) *OrderTrackingController {
+	return &OrderTrackingController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+// Stream Server-Sent Events status order customer, ex: /customer/orders/stream?order_ids=<id>,<id>.
+// Event "status" punya id, jadi EventSource yang reconnect otomatis kirim Last-Event-ID dan event yang terlewat di-replay.
+// Setiap koneksi juga dapat event "snapshot" berisi status terbaru semua order yang dipantau.
+func (c *OrderTrackingController) Stream(ctx *fiber.Ctx) error {
+	customerID, _ := ctx.Locals("userID").(string)
+
+	request := &model.TrackOrderRequest{
+		LastEventID: ctx.Get("Last-Event-ID", ctx.Query("last_event_id")),
+	}
+	for _, orderID := range strings.Split(ctx.Query("order_ids"), ",") {
+		if orderID = strings.TrimSpace(orderID); orderID != "" {
+			request.OrderIDs = append(request.OrderIDs, orderID)
+		}
+	}
+
+	tracking, err := c.UseCase.Track(ctx.Context(), customerID, request)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrUnauthorized):
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+		case errors.Is(err, utils.ErrNotFound):
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "order not found"))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	setSSEHeaders(ctx)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer tracking.Subscription.Close()
+
+		keepAlive := time.NewTicker(sseKeepAliveInterval)
+		defer keepAlive.Stop()
+
+		for _, event := range tracking.Replay {
+			if err := writeSSE(w, strconv.FormatInt(event.ID, 10), "status", converter.OrderEventToStatusEvent(event)); err != nil {
+				return
+			}
+		}
+		if err := writeSSE(w, "", "snapshot", tracking.Snapshot); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case event, ok := <-tracking.Subscription.Events():
+				if !ok {
+					return
+				}
+				if !tracking.Accept(event) {
+					continue
+				}
+				if err := writeSSE(w, strconv.FormatInt(event.ID, 10), "status", converter.OrderEventToStatusEvent(event)); err != nil {
+					return
+				}
+			case <-keepAlive.C:
+				if err := writeSSEKeepAlive(w); err != nil {
+					return
+				}
+			}
+		}
+	})
+
+	return nil
+}
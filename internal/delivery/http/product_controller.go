@@ -129,6 +129,7 @@ func (c *ProductController) Update(ctx *fiber.Ctx) error {
 	stockStr := ctx.FormValue("stock")
 	description := ctx.FormValue("description")
 	categoryIDStr := ctx.FormValue("category_id")
+	isActiveStr := ctx.FormValue("is_active")
 
 	var price, stock int
 	if priceStr != "" {
@@ -148,6 +149,16 @@ func (c *ProductController) Update(ctx *fiber.Ctx) error {
 		categoryID = parsed
 	}
 
+	var isActive *bool
+	if isActiveStr != "" {
+		parsed, err := strconv.ParseBool(isActiveStr)
+		if err != nil {
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Invalid is_active"))
+		}
+		isActive = &parsed
+	}
+
 	// File opsional
 	file, _ := ctx.FormFile("image")
 
@@ -161,6 +172,7 @@ func (c *ProductController) Update(ctx *fiber.Ctx) error {
 		Stock:       stock,
 		Description: description,
 		CategoryID:  categoryID,
+		IsActive:    isActive,
 	}
 
 	err := c.UseCase.Update(ctx.Context(), id, request, file)
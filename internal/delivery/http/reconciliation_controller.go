@@ -0,0 +1,91 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type ReconciliationController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.ReconciliationUseCase
+}
+
+func NewReconciliationController(useCase *usecase.ReconciliationUseCase, logger *logrus.Logger) *ReconciliationController {
+	return &ReconciliationController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *ReconciliationController) Run(ctx *fiber.Ctx) error {
+	request := new(model.RunReconciliationRequest)
+	userID, _ := ctx.Locals("userID").(string)
+
+	err := ctx.BodyParser(request)
+	if err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
+	}
+
+	response, err := c.UseCase.Run(ctx.UserContext(), "cms:"+userID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to run reconciliation : %+v", err)
+
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrConflict):
+			return ctx.Status(fiber.StatusConflict).
+				JSON(utils.ErrorResponse(fiber.StatusConflict, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	// diproses di background, cek hasilnya lewat GET /reconciliations/:id
+	return ctx.Status(fiber.StatusAccepted).
+		JSON(utils.SuccessResponse(fiber.StatusAccepted, "reconciliation queued", response))
+}
+
+func (c *ReconciliationController) FindAll(ctx *fiber.Ctx) error {
+	req := &utils.PaginationRequest{
+		Page:  ctx.QueryInt("page", 1),
+		Limit: ctx.QueryInt("limit", 10),
+	}
+
+	runs, pagination, err := c.UseCase.FindAll(ctx.Context(), req)
+	if err != nil {
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponseWithPagination(fiber.StatusOK, "get list reconciliation successfully", runs, pagination))
+}
+
+func (c *ReconciliationController) FindByID(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+
+	run, err := c.UseCase.FindByID(ctx.Context(), id)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "reconciliation not found"))
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, err.Error()))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponse(fiber.StatusOK, "get detail reconciliation successfully", run))
+}
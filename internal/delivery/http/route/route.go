@@ -6,19 +6,33 @@ import (
 )
 
 type RouteConfig struct {
-	App                *fiber.App
-	AuthController     *http.AuthController
-	CustomerController *http.CustomerController
-	UserController     *http.UserController
-	CategoryController *http.CategoryController
-	ProductController  *http.ProductController
-	OrderController    *http.OrderController
-	AuthMiddleware     fiber.Handler
+	App                      *fiber.App
+	AuthController           *http.AuthController
+	CustomerController       *http.CustomerController
+	UserController           *http.UserController
+	CategoryController       *http.CategoryController
+	ProductController        *http.ProductController
+	VoucherController        *http.VoucherController
+	ShippingZoneController   *http.ShippingZoneController
+	PickupSlotController     *http.PickupSlotController
+	WalletController         *http.WalletController
+	LoyaltyController        *http.LoyaltyController
+	OrderController          *http.OrderController
+	OrderTrackingController  *http.OrderTrackingController
+	KitchenController        *http.KitchenController
+	OrderDocumentController  *http.OrderDocumentController
+	ReconciliationController *http.ReconciliationController
+	AuthMiddleware           fiber.Handler
+	IdempotencyMiddleware    fiber.Handler
+	KitchenMiddleware        fiber.Handler
+	StaffMiddleware          fiber.Handler
+	FinanceMiddleware        fiber.Handler
 }
 
 func (c *RouteConfig) Setup() {
 	c.SetupAuthRoute()
 	c.SetupGuestRoute()
+	c.SetupCustomerRoute()
 	c.SetupCMSRoute()
 }
 
@@ -34,8 +48,35 @@ func (c *RouteConfig) SetupGuestRoute() {
 	product.Get(":id", c.ProductController.FindByID)
 	product.Get("/product/special", c.ProductController.FindSpecialProduct)
 
+	pickupSlot := guest.Group("/pickup-slots")
+	pickupSlot.Get("", c.PickupSlotController.FindAvailable)
+
 	order := guest.Group("/orders")
-	order.Post("", c.OrderController.Create)
+	order.Post("", c.IdempotencyMiddleware, c.OrderController.Create)
+	order.Post("/notification", c.OrderController.Notification)
+}
+
+// SetupCustomerRoute endpoint milik customer yang sudah login, semua data di-scope ke user dari token
+func (c *RouteConfig) SetupCustomerRoute() {
+	api := c.App.Group("/api/v1")
+	customer := api.Group("/customer", c.AuthMiddleware)
+
+	order := customer.Group("/orders")
+	order.Post("", c.IdempotencyMiddleware, c.OrderController.CreateForCustomer)
+	order.Get("", c.OrderController.FindAllForCustomer)
+	order.Get("/stream", c.OrderTrackingController.Stream) // sebelum :id
+	order.Get(":id", c.OrderController.FindByIDForCustomer)
+	order.Get(":id/invoice", c.OrderDocumentController.CustomerInvoice)
+	order.Get(":id/receipt", c.OrderDocumentController.CustomerReceipt)
+
+	wallet := customer.Group("/wallet")
+	wallet.Get("", c.WalletController.FindForCustomer)
+	wallet.Get("/entries", c.WalletController.FindEntriesForCustomer)
+	wallet.Post("/top-ups", c.IdempotencyMiddleware, c.WalletController.TopUp)
+
+	loyalty := customer.Group("/loyalty")
+	loyalty.Get("", c.LoyaltyController.FindForCustomer)
+	loyalty.Get("/transactions", c.LoyaltyController.FindTransactionsForCustomer)
 }
 
 func (c *RouteConfig) SetupAuthRoute() {
@@ -79,10 +120,56 @@ func (c *RouteConfig) SetupCMSRoute() {
 	product.Get("/product/special", c.ProductController.FindSpecialProduct)
 	product.Put("/product/special", c.ProductController.UpdateSpecialProduct)
 
+	voucher := cms.Group("/vouchers", c.StaffMiddleware)
+	voucher.Post("", c.VoucherController.Create)
+	voucher.Get("", c.VoucherController.FindAll)
+	voucher.Get(":id", c.VoucherController.FindByID)
+	voucher.Put(":id", c.VoucherController.Update)
+	voucher.Delete(":id", c.VoucherController.Delete)
+
+	shippingZone := cms.Group("/shipping-zones", c.StaffMiddleware)
+	shippingZone.Post("", c.ShippingZoneController.Create)
+	shippingZone.Get("", c.ShippingZoneController.FindAll)
+	shippingZone.Get(":id", c.ShippingZoneController.FindByID)
+	shippingZone.Put(":id", c.ShippingZoneController.Update)
+	shippingZone.Delete(":id", c.ShippingZoneController.Delete)
+
+	pickupSlot := cms.Group("/pickup-slots", c.StaffMiddleware)
+	pickupSlot.Post("", c.PickupSlotController.Create)
+	pickupSlot.Get("", c.PickupSlotController.FindAll)
+	pickupSlot.Get(":id", c.PickupSlotController.FindByID)
+	pickupSlot.Put(":id", c.PickupSlotController.Update)
+	pickupSlot.Delete(":id", c.PickupSlotController.Delete)
+
 	customer := cms.Group("/customers")
 	customer.Post("", c.CustomerController.Register)
 	customer.Get("", c.CustomerController.FindAll)
 	customer.Get(":id", c.CustomerController.FindByID)
 	customer.Put(":id", c.CustomerController.Update)
 	customer.Delete(":id", c.CustomerController.Delete)
+	customer.Get(":id/wallet", c.WalletController.FindByCustomerID)
+	customer.Get(":id/wallet/entries", c.WalletController.FindEntries)
+	customer.Get(":id/loyalty", c.LoyaltyController.FindByCustomerID)
+	customer.Get(":id/loyalty/transactions", c.LoyaltyController.FindTransactions)
+	customer.Post(":id/loyalty/adjustments", c.StaffMiddleware, c.LoyaltyController.Adjust)
+
+	order := cms.Group("/orders", c.StaffMiddleware)
+	order.Get("", c.OrderController.FindAll)
+	order.Get("/scheduled", c.OrderController.FindScheduled) // sebelum :id
+	order.Get(":id", c.OrderController.FindByID)
+	order.Put(":id/status", c.OrderController.UpdateStatus)
+	order.Get(":id/invoice", c.OrderDocumentController.Invoice)
+	order.Get(":id/receipt", c.OrderDocumentController.Receipt)
+	order.Post(":id/cancel", c.FinanceMiddleware, c.OrderController.Cancel)
+	order.Post(":id/refund", c.FinanceMiddleware, c.OrderController.Refund)
+
+	kitchen := cms.Group("/kitchen", c.KitchenMiddleware)
+	kitchen.Get("/orders", c.KitchenController.FindQueue)
+	kitchen.Put("/orders/:id/status", c.KitchenController.Move)
+	kitchen.Get("/stream", c.KitchenController.Stream)
+
+	reconciliation := cms.Group("/reconciliations", c.FinanceMiddleware)
+	reconciliation.Post("", c.ReconciliationController.Run)
+	reconciliation.Get("", c.ReconciliationController.FindAll)
+	reconciliation.Get(":id", c.ReconciliationController.FindByID)
 }
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sseKeepAliveInterval comment kosong dikirim berkala supaya proxy tidak menutup koneksi yang idle
+const sseKeepAliveInterval = 15 * time.Second
+
+func setSSEHeaders(ctx *fiber.Ctx) {
+	ctx.Set(fiber.HeaderContentType, "text/event-stream")
+	ctx.Set(fiber.HeaderCacheControl, "no-cache")
+	ctx.Set(fiber.HeaderConnection, "keep-alive")
+	ctx.Set("X-Accel-Buffering", "no") // nginx jangan buffer response
+}
+
+// writeSSE tulis satu event lalu flush, error berarti client sudah putus
+func writeSSE(w *bufio.Writer, id, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	return w.Flush()
+}
+
+func writeSSEKeepAlive(w *bufio.Writer) error {
+	fmt.Fprint(w, ": keep-alive\n\n")
+	return w.Flush()
+}
@@ -0,0 +1,146 @@
+package http
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+)
+
+type WalletController struct {
+	Log     *logrus.Logger
+	UseCase *usecase.WalletUseCase
+}
+
+func NewWalletController(useCase *usecase.WalletUseCase, logger *logrus.Logger) *WalletController {
+	return &WalletController{
+		Log:     logger,
+		UseCase: useCase,
+	}
+}
+
+func (c *WalletController) FindForCustomer(ctx *fiber.Ctx) error {
+	customerID, _ := ctx.Locals("userID").(string)
+
+	wallet, err := c.UseCase.FindByCustomerID(ctx.Context(), customerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrUnauthorized):
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponse(fiber.StatusOK, "get wallet successfully", wallet))
+}
+
+func (c *WalletController) FindEntriesForCustomer(ctx *fiber.Ctx) error {
+	customerID, _ := ctx.Locals("userID").(string)
+
+	req := &utils.PaginationRequest{
+		Page:  ctx.QueryInt("page", 1),
+		Limit: ctx.QueryInt("limit", 10),
+	}
+
+	entries, pagination, err := c.UseCase.FindEntries(ctx.Context(), customerID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, utils.ErrUnauthorized):
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponseWithPagination(fiber.StatusOK, "get list wallet entry successfully", entries, pagination))
+}
+
+func (c *WalletController) TopUp(ctx *fiber.Ctx) error {
+	request := new(model.TopUpWalletRequest)
+	customerID, _ := ctx.Locals("userID").(string)
+
+	err := ctx.BodyParser(request)
+	if err != nil {
+		c.Log.Warnf("Failed to parse request body : %+v", err)
+		return ctx.Status(fiber.StatusBadRequest).JSON(utils.ErrorResponse(fiber.StatusBadRequest, "Failed to parse request body"))
+	}
+
+	response, err := c.UseCase.TopUp(ctx.UserContext(), customerID, request)
+	if err != nil {
+		c.Log.Warnf("Failed to top up wallet : %+v", err)
+
+		switch {
+		case errors.Is(err, utils.ErrValidation):
+			return ctx.Status(fiber.StatusBadRequest).
+				JSON(utils.ErrorResponse(fiber.StatusBadRequest, err.Error()))
+
+		case errors.Is(err, utils.ErrUnauthorized):
+			return ctx.Status(fiber.StatusUnauthorized).
+				JSON(utils.ErrorResponse(fiber.StatusUnauthorized, err.Error()))
+
+		case errors.Is(err, utils.ErrPayment):
+			return ctx.Status(fiber.StatusBadGateway).
+				JSON(utils.ErrorResponse(fiber.StatusBadGateway, err.Error()))
+
+		default: // internal error
+			return ctx.Status(fiber.StatusInternalServerError).
+				JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+		}
+	}
+
+	return ctx.Status(fiber.StatusCreated).
+		JSON(utils.SuccessResponse(fiber.StatusCreated, "top up created successfully", response))
+}
+
+func (c *WalletController) FindByCustomerID(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+
+	wallet, err := c.UseCase.FindByCustomerIDForCMS(ctx.Context(), id)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "customer not found"))
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponse(fiber.StatusOK, "get wallet successfully", wallet))
+}
+
+func (c *WalletController) FindEntries(ctx *fiber.Ctx) error {
+	id := ctx.Params("id")
+
+	req := &utils.PaginationRequest{
+		Page:  ctx.QueryInt("page", 1),
+		Limit: ctx.QueryInt("limit", 10),
+	}
+
+	entries, pagination, err := c.UseCase.FindEntriesForCMS(ctx.Context(), id, req)
+	if err != nil {
+		if errors.Is(err, utils.ErrNotFound) {
+			return ctx.Status(fiber.StatusNotFound).
+				JSON(utils.ErrorResponse(fiber.StatusNotFound, "customer not found"))
+		}
+
+		return ctx.Status(fiber.StatusInternalServerError).
+			JSON(utils.ErrorResponse(fiber.StatusInternalServerError, "internal server error"))
+	}
+
+	return ctx.Status(fiber.StatusOK).
+		JSON(utils.SuccessResponseWithPagination(fiber.StatusOK, "get list wallet entry successfully", entries, pagination))
+}
@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+const loyaltyExpiryBatchSize = 100
+
+type LoyaltyExpiryWorker struct {
+	Log      *logrus.Logger
+	UseCase  *usecase.LoyaltyUseCase
+	Interval time.Duration
+}
+
+func NewLoyaltyExpiryWorker(useCase *usecase.LoyaltyUseCase, logger *logrus.Logger, interval time.Duration) *LoyaltyExpiryWorker {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &LoyaltyExpiryWorker{
+		Log:      logger,
+		UseCase:  useCase,
+		Interval: interval,
+	}
+}
+
+func (w *LoyaltyExpiryWorker) Name() string {
+	return "loyalty-expiry"
+}
+
+func (w *LoyaltyExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *LoyaltyExpiryWorker) sweep(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, w.Interval)
+	defer cancel()
+
+	expired, err := w.UseCase.ExpirePoints(ctx, loyaltyExpiryBatchSize)
+	if err != nil {
+		w.Log.Warnf("Failed sweep expired loyalty points : %+v", err)
+		return
+	}
+	if expired > 0 {
+		w.Log.Infof("Loyalty expiry worker expired points of %d account(s)", expired)
+	}
+}
@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+const orderExpiryBatchSize = 100
+
+// OrderExpiryWorker sweep order & top up wallet yang sudah lewat batas waktu bayar
+type OrderExpiryWorker struct {
+	Log           *logrus.Logger
+	UseCase       *usecase.OrderUseCase
+	WalletUseCase *usecase.WalletUseCase
+	Interval      time.Duration
+}
+
+func NewOrderExpiryWorker(useCase *usecase.OrderUseCase, walletUseCase *usecase.WalletUseCase, logger *logrus.Logger, interval time.Duration) *OrderExpiryWorker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &OrderExpiryWorker{
+		Log:           logger,
+		UseCase:       useCase,
+		WalletUseCase: walletUseCase,
+		Interval:      interval,
+	}
+}
+
+func (w *OrderExpiryWorker) Name() string {
+	return "order-expiry"
+}
+
+func (w *OrderExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *OrderExpiryWorker) sweep(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, w.Interval)
+	defer cancel()
+
+	moved, err := w.UseCase.ExpirePendingOrders(ctx, orderExpiryBatchSize)
+	if err != nil {
+		w.Log.Warnf("Failed sweep expired orders : %+v", err)
+	} else if moved > 0 {
+		w.Log.Infof("Expiry worker updated %d order(s)", moved)
+	}
+
+	moved, err = w.WalletUseCase.ExpirePendingTopUps(ctx, orderExpiryBatchSize)
+	if err != nil {
+		w.Log.Warnf("Failed sweep expired top ups : %+v", err)
+	} else if moved > 0 {
+		w.Log.Infof("Expiry worker updated %d top up(s)", moved)
+	}
+}
@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// lock run harian per tanggal, dipegang sampai besok supaya instance lain tidak menjalankan tanggal yang sama
+const reconciliationLockTTL = 24 * time.Hour
+
+// ReconciliationWorker jalan sekali sehari di jam tertentu (timezone toko), merekonsiliasi order & top up kemarin.
+// Di luar jadwal itu worker memproses run manual dari CMS yang masuk antrian.
+// Kalau API jalan di beberapa instance, run harian hanya dijalankan instance yang dapat lock di Redis.
+type ReconciliationWorker struct {
+	Log         *logrus.Logger
+	UseCase     *usecase.ReconciliationUseCase
+	RedisClient *redis.Client
+	Location    *time.Location
+	Hour        int
+}
+
+func NewReconciliationWorker(useCase *usecase.ReconciliationUseCase, redisClient *redis.Client, logger *logrus.Logger,
+	location *time.Location, hour int) *ReconciliationWorker {
+	if hour < 0 || hour > 23 {
+		hour = 2
+	}
+	return &ReconciliationWorker{
+		Log:         logger,
+		UseCase:     useCase,
+		RedisClient: redisClient,
+		Location:    location,
+		Hour:        hour,
+	}
+}
+
+func (w *ReconciliationWorker) Name() string {
+	return "reconciliation"
+}
+
+func (w *ReconciliationWorker) Run(ctx context.Context) {
+	// run manual yang masih queued sebelum restart
+	w.processQueued(ctx)
+
+	for {
+		next := w.nextRun(time.Now())
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			w.reconcile(ctx)
+		case <-w.UseCase.Queued():
+			timer.Stop()
+			w.processQueued(ctx)
+		}
+	}
+}
+
+func (w *ReconciliationWorker) nextRun(now time.Time) time.Time {
+	local := now.In(w.Location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), w.Hour, 0, 0, 0, w.Location)
+	if !next.After(local) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func (w *ReconciliationWorker) reconcile(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+
+	now := time.Now()
+	day := now.In(w.Location).AddDate(0, 0, -1).Format("2006-01-02")
+	acquired, err := w.RedisClient.SetNX(ctx, "reconciliation:daily:"+day, w.Name(), reconciliationLockTTL).Result()
+	if err != nil {
+		w.Log.Warnf("Failed acquire daily reconciliation lock, date=%s : %+v", day, err)
+		return
+	}
+	if !acquired {
+		w.Log.Infof("Daily reconciliation for %s is already handled by another instance", day)
+		return
+	}
+
+	if _, err := w.UseCase.RunDaily(ctx, now); err != nil {
+		w.Log.Warnf("Failed run daily reconciliation : %+v", err)
+	}
+}
+
+// processQueued proses run queued satu per satu sampai antrian kosong
+func (w *ReconciliationWorker) processQueued(ctx context.Context) {
+	for ctx.Err() == nil {
+		if !w.process(ctx) {
+			return
+		}
+	}
+}
+
+func (w *ReconciliationWorker) process(ctx context.Context) bool {
+	ctx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+
+	processed, err := w.UseCase.ProcessNext(ctx)
+	if err != nil {
+		w.Log.Warnf("Failed run queued reconciliation : %+v", err)
+	}
+	return processed
+}
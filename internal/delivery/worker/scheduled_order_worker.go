@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/usecase"
+	"github.com/sirupsen/logrus"
+)
+
+const scheduledOrderBatchSize = 100
+
+// ScheduledOrderWorker rilis pre-order yang sudah dibayar ke antrian barista saat masuk prep window
+type ScheduledOrderWorker struct {
+	Log      *logrus.Logger
+	UseCase  *usecase.OrderUseCase
+	Interval time.Duration
+}
+
+func NewScheduledOrderWorker(useCase *usecase.OrderUseCase, logger *logrus.Logger, interval time.Duration) *ScheduledOrderWorker {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &ScheduledOrderWorker{
+		Log:      logger,
+		UseCase:  useCase,
+		Interval: interval,
+	}
+}
+
+func (w *ScheduledOrderWorker) Name() string {
+	return "scheduled-order-release"
+}
+
+func (w *ScheduledOrderWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.sweep(ctx)
+		}
+	}
+}
+
+func (w *ScheduledOrderWorker) sweep(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, w.Interval)
+	defer cancel()
+
+	released, err := w.UseCase.ReleaseScheduledOrders(ctx, scheduledOrderBatchSize)
+	if err != nil {
+		w.Log.Warnf("Failed release scheduled orders : %+v", err)
+		return
+	}
+	if released > 0 {
+		w.Log.Infof("Released %d scheduled order(s) to kitchen queue", released)
+	}
+}
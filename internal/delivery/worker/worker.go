@@ -0,0 +1,47 @@
+package worker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Worker job background yang jalan sampai context di-cancel
+type Worker interface {
+	Name() string
+	Run(ctx context.Context)
+}
+
+type Runner struct {
+	Log     *logrus.Logger
+	workers []Worker
+	wg      sync.WaitGroup
+}
+
+func NewRunner(logger *logrus.Logger) *Runner {
+	return &Runner{
+		Log: logger,
+	}
+}
+
+func (r *Runner) Register(w Worker) {
+	r.workers = append(r.workers, w)
+}
+
+func (r *Runner) Start(ctx context.Context) {
+	for _, w := range r.workers {
+		r.wg.Add(1)
+		go func(w Worker) {
+			defer r.wg.Done()
+			r.Log.Infof("Worker %s started", w.Name())
+			w.Run(ctx)
+			r.Log.Infof("Worker %s stopped", w.Name())
+		}(w)
+	}
+}
+
+// Wait tunggu semua worker selesai setelah context di-cancel
+func (r *Runner) Wait() {
+	r.wg.Wait()
+}
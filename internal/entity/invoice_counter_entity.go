@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+// InvoiceCounter nomor urut invoice per prefix per hari (timezone toko)
+type InvoiceCounter struct {
+	Prefix    string `gorm:"size:20;primaryKey"`
+	Day       string `gorm:"size:10;primaryKey"` // YYYY-MM-DD
+	Value     int64  `gorm:"not null;default:0"`
+	UpdatedAt time.Time
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jenis mutasi poin loyalty
+const (
+	LoyaltyTypeEarn    = "earn"    // order paid
+	LoyaltyTypeRedeem  = "redeem"  // dipakai sebagai diskon di checkout
+	LoyaltyTypeRestore = "restore" // poin redeem dikembalikan (order gagal / refund)
+	LoyaltyTypeReverse = "reverse" // poin earn ditarik karena order di-refund
+	LoyaltyTypeExpire  = "expire"
+	LoyaltyTypeAdjust  = "adjust" // manual dari CMS
+)
+
+// ActorLoyaltyExpiryWorker actor untuk poin yang expire
+const ActorLoyaltyExpiryWorker = "system:loyalty-expiry-worker"
+
+// LoyaltyAccount saldo poin per customer, Balance selalu sama dengan total Remaining di LoyaltyTransaction
+type LoyaltyAccount struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CustomerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	Balance    int64     `gorm:"not null;default:0"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// LoyaltyTransaction mutasi poin. Transaksi positif sekaligus jadi "lot" dengan tanggal expire sendiri,
+// poin keluar mengurangi Remaining lot paling lama dulu (FIFO).
+type LoyaltyTransaction struct {
+	ID           uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	AccountID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Type         string     `gorm:"size:20;not null"`
+	Points       int64      `gorm:"not null"` // positif = tambah, negatif = kurang
+	BalanceAfter int64      `gorm:"not null"`
+	Remaining    int64      `gorm:"not null;default:0"` // sisa poin lot yang belum terpakai / expire
+	ExpiresAt    *time.Time `gorm:"default:null;index"`
+	OrderID      *uuid.UUID `gorm:"type:uuid;default:null;index"`
+	Reason       string     `gorm:"size:255"`
+	Actor        string     `gorm:"size:100;not null"`
+	CreatedAt    time.Time
+}
@@ -8,41 +8,130 @@ import (
 )
 
 const (
-	OrderStatusPending = "pending"
-	OrderStatusPaid    = "paid"
-	OrderStatusFailed  = "failed"
-	OrderStatusExpired = "expired"
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusPreparing = "preparing"
+	OrderStatusReady     = "ready"
+	OrderStatusCompleted = "completed"
+	OrderStatusFailed    = "failed"
+	OrderStatusExpired   = "expired"
+	OrderStatusCancelled = "cancelled"
+	OrderStatusRefunded  = "refunded"
 )
 
+const (
+	CheckoutModeCore = "core" // charge langsung lewat Core API, UI pembayaran di app kita
+	CheckoutModeSnap = "snap" // customer bayar di halaman Snap Midtrans
+)
+
+// orderTransitions status tujuan yang boleh dari tiap status, status yang tidak ada di sini berarti final
+var orderTransitions = map[string][]string{
+	OrderStatusPending:   {OrderStatusPaid, OrderStatusFailed, OrderStatusExpired, OrderStatusCancelled},
+	OrderStatusPaid:      {OrderStatusPreparing, OrderStatusRefunded},
+	OrderStatusPreparing: {OrderStatusReady, OrderStatusRefunded},
+	OrderStatusReady:     {OrderStatusCompleted, OrderStatusRefunded},
+	OrderStatusCompleted: {OrderStatusRefunded},
+}
+
+// KitchenStatuses status order yang tampil di layar barista, paid = order baru yang belum mulai dibuat
+var KitchenStatuses = []string{OrderStatusPaid, OrderStatusPreparing, OrderStatusReady}
+
+// actor yang tercatat di OrderStatusHistory, selain "cms:<user_id>" & "customer:<customer_id>"
+const (
+	ActorMidtransCharge       = "midtrans:charge"
+	ActorMidtransNotification = "midtrans:notification"
+	ActorExpiryWorker         = "system:expiry-worker"
+)
+
+const (
+	StockStatusReserved  = "reserved"  // stok di-hold selama menunggu pembayaran
+	StockStatusCommitted = "committed" // stok sudah benar-benar dikurangi (order paid)
+	StockStatusReleased  = "released"  // hold dilepas (failed / expired / cancelled)
+)
+
+func (Order) SearchFields() []string {
+	return []string{"invoice_number"}
+}
+
+// CanTransitionTo cek apakah status order boleh pindah ke status tujuan
+func (o *Order) CanTransitionTo(to string) bool {
+	for _, next := range orderTransitions[o.Status] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
 type Order struct {
-	ID            uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	UserID        uuid.UUID    `gorm:"type:uuid;not null"`                 // siapa yang order
-	InvoiceNumber string       `gorm:"size:50;unique;not null"`            // kode unik, misal: INV-20250908-0001
-	Status        string       `gorm:"size:20;not null;default:'pending'"` // pending, paid, failed, expired
-	Amount        int64        `gorm:"not null"`                           // total harga
-	PaymentMethod string       `gorm:"size:50"`                            // ex: bank_transfer
-	PaymentType   string       `gorm:"size:50"`                            // ex: bca, gopay, shopeepay
-	TransactionID string       `gorm:"size:100"`                           // dari Midtrans
-	RedirectURL   string       `gorm:"size:255"`                           // kalau pakai Snap
-	ExpiredAt     *time.Time   `gorm:"default:null"`
-	Notes         string       `gorm:"size:255"`
-	ShippingAddr  string       `gorm:"size:255"`
-	OrderItems    []OrderItem  `gorm:"foreignKey:OrderID"`
-	PaymentLogs   []PaymentLog `gorm:"foreignKey:OrderID"`
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
+	ID                uuid.UUID            `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID            uuid.UUID            `gorm:"type:uuid;not null"`                 // siapa yang order
+	CustomerName      string               `gorm:"size:100"`                           // snapshot data customer saat checkout, dipakai di invoice / receipt
+	CustomerEmail     string               `gorm:"size:100"`                           // snapshot
+	CustomerPhone     string               `gorm:"size:20"`                            // snapshot
+	InvoiceNumber     string               `gorm:"size:50;unique;not null"`            // kode unik, misal: INV-20250908-0001
+	Status            string               `gorm:"size:20;not null;default:'pending'"` // pending, paid, preparing, ready, completed, failed, expired, cancelled, refunded
+	Subtotal          int64                `gorm:"not null;default:0"`                 // jumlah qty * price semua item
+	DiscountAmount    int64                `gorm:"not null;default:0"`
+	VoucherID         *uuid.UUID           `gorm:"type:uuid;default:null"` // voucher yang dipakai, kalau ada
+	VoucherCode       string               `gorm:"size:50"`
+	PointsRedeemed    int64                `gorm:"not null;default:0"` // poin loyalty yang dipakai, nilainya sudah masuk DiscountAmount
+	PointsDiscount    int64                `gorm:"not null;default:0"`
+	PointsEarned      int64                `gorm:"not null;default:0"` // poin yang didapat saat order paid
+	PointsReversed    int64                `gorm:"not null;default:0"` // poin earn yang ditarik lagi karena refund
+	PointsRestored    int64                `gorm:"not null;default:0"` // poin redeem yang dikembalikan ke customer
+	ServiceCharge     int64                `gorm:"not null;default:0"`
+	TaxAmount         int64                `gorm:"not null;default:0"` // kalau inclusive hanya informasi, sudah ada di subtotal
+	DeliveryFee       int64                `gorm:"not null;default:0"`
+	RoundingAmount    int64                `gorm:"not null;default:0"` // bisa negatif
+	TaxInclusive      bool                 `gorm:"not null;default:false"`
+	TaxRate           int64                `gorm:"not null;default:0"`              // basis point saat order dibuat, 1100 = 11%
+	ServiceRate       int64                `gorm:"not null;default:0"`              // basis point
+	Amount            int64                `gorm:"not null"`                        // total yang dibayar (gross amount)
+	RefundedAmount    int64                `gorm:"not null;default:0"`              // total yang sudah di-refund
+	PaymentMethod     string               `gorm:"size:50"`                         // ex: bank_transfer
+	PaymentType       string               `gorm:"size:50"`                         // ex: bca, gopay, shopeepay
+	TransactionID     string               `gorm:"size:100"`                        // dari Midtrans
+	CheckoutMode      string               `gorm:"size:10;not null;default:'core'"` // core, snap
+	SnapToken         string               `gorm:"size:100"`                        // kalau pakai Snap
+	RedirectURL       string               `gorm:"size:255"`                        // kalau pakai Snap
+	QRString          string               `gorm:"type:text"`                       // qris / gopay
+	QRURL             string               `gorm:"size:255"`                        // url gambar QR dari Midtrans
+	DeeplinkURL       string               `gorm:"size:255"`                        // e-wallet
+	VANumber          string               `gorm:"size:50"`                         // bank transfer
+	VABank            string               `gorm:"size:20"`                         // bca, bni, bri, permata
+	ExpiredAt         *time.Time           `gorm:"default:null"`
+	Notes             string               `gorm:"size:255"`
+	FulfillmentType   string               `gorm:"size:20;not null;default:'delivery'"` // delivery, pickup, dine_in
+	PickupSlotID      *uuid.UUID           `gorm:"type:uuid;default:null;index:idx_order_pickup"`
+	PickupAt          *time.Time           `gorm:"default:null;index:idx_order_pickup"` // waktu mulai slot pickup yang dipilih
+	ScheduledFor      *time.Time           `gorm:"default:null;index"`                  // pre-order, waktu order harus siap
+	KitchenReleasedAt *time.Time           `gorm:"default:null"`                        // pre-order mulai tampil di antrian barista
+	ShippingAddr      string               `gorm:"size:255"`
+	ShippingCity      string               `gorm:"size:100"`
+	ShippingPostal    string               `gorm:"size:10"`
+	ShippingZoneID    *uuid.UUID           `gorm:"type:uuid;default:null"` // zone yang dipakai hitung ongkir
+	StockStatus       string               `gorm:"size:20"`                // reserved, committed, released
+	OrderItems        []OrderItem          `gorm:"foreignKey:OrderID"`
+	PaymentLogs       []PaymentLog         `gorm:"foreignKey:OrderID"`
+	Histories         []OrderStatusHistory `gorm:"foreignKey:OrderID"`
+	Refunds           []Refund             `gorm:"foreignKey:OrderID"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
 }
 
 type OrderItem struct {
-	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	OrderID     uuid.UUID `gorm:"type:uuid;not null"`
-	ProductID   uuid.UUID `gorm:"type:uuid;not null"`
-	ProductName string    `gorm:"size:100;not null"` // disimpan biar invoice tetap valid kalau product berubah
-	Qty         int       `gorm:"not null"`
-	Price       int64     `gorm:"not null"` // harga per unit saat order
-	Subtotal    int64     `gorm:"not null"` // qty * price
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderID        uuid.UUID `gorm:"type:uuid;not null"`
+	ProductID      uuid.UUID `gorm:"type:uuid;not null"`
+	ProductName    string    `gorm:"size:100;not null"` // disimpan biar invoice tetap valid kalau product berubah
+	Qty            int       `gorm:"not null"`
+	Price          int64     `gorm:"not null"`           // harga per unit saat order
+	Subtotal       int64     `gorm:"not null"`           // qty * price
+	RefundedQty    int       `gorm:"not null;default:0"` // qty yang sudah di-refund
+	RefundedAmount int64     `gorm:"not null;default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
 }
 
 type PaymentLog struct {
@@ -53,3 +142,42 @@ type PaymentLog struct {
 	RawResponse         datatypes.JSON `gorm:"type:jsonb"`
 	CreatedAt           time.Time
 }
+
+// OrderStatusHistory audit trail perubahan status order
+type OrderStatusHistory struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderID    uuid.UUID `gorm:"type:uuid;index;not null"`
+	FromStatus string    `gorm:"size:20"`
+	ToStatus   string    `gorm:"size:20;not null"`
+	Reason     string    `gorm:"size:255"`
+	Actor      string    `gorm:"size:100;not null"` // ex: cms:<user_id>, midtrans:notification
+	CreatedAt  time.Time
+}
+
+// Refund satu kali request refund ke Midtrans, bisa full atau sebagian item
+type Refund struct {
+	ID          uuid.UUID    `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	OrderID     uuid.UUID    `gorm:"type:uuid;index;not null"`
+	RefundKey   string       `gorm:"size:60;unique;not null"` // ex: INV-20250908-0001-R1
+	Amount      int64        `gorm:"not null"`
+	Status      string       `gorm:"size:20"`                             // status dari Midtrans: refund, partial_refund
+	Destination string       `gorm:"size:20;not null;default:'original'"` // original (metode bayar awal), wallet
+	Reason      string       `gorm:"size:255"`
+	Actor       string       `gorm:"size:100;not null"`
+	Items       []RefundItem `gorm:"foreignKey:RefundID"`
+	CreatedAt   time.Time
+}
+
+const (
+	RefundDestinationOriginal = "original"
+	RefundDestinationWallet   = "wallet"
+)
+
+type RefundItem struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	RefundID    uuid.UUID `gorm:"type:uuid;index;not null"`
+	OrderItemID uuid.UUID `gorm:"type:uuid;not null"`
+	Qty         int       `gorm:"not null"`
+	Amount      int64     `gorm:"not null"`
+	CreatedAt   time.Time
+}
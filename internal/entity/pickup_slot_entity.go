@@ -0,0 +1,49 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	FulfillmentDelivery = "delivery"
+	FulfillmentPickup   = "pickup"
+	FulfillmentDineIn   = "dine_in"
+)
+
+// PickupSlotTimeLayout format jam mulai / selesai slot, jam toko (STORE_TIMEZONE)
+const PickupSlotTimeLayout = "15:04"
+
+// Implement Searchable
+func (PickupSlot) SearchFields() []string {
+	return []string{"name"}
+}
+
+// PickupSlot jendela waktu ambil pesanan yang berulang tiap hari, Capacity = maksimal order per slot per hari
+type PickupSlot struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name      string    `gorm:"size:100;not null"`
+	StartTime string    `gorm:"size:5;not null"` // ex: 08:00
+	EndTime   string    `gorm:"size:5;not null"` // ex: 08:30
+	Capacity  int       `gorm:"not null"`
+	IsActive  bool      `gorm:"not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// StartOn waktu mulai slot di tanggal day, timezone ikut day
+func (s *PickupSlot) StartOn(day time.Time) time.Time {
+	return slotTimeOn(day, s.StartTime)
+}
+
+// EndOn waktu selesai slot di tanggal day, timezone ikut day
+func (s *PickupSlot) EndOn(day time.Time) time.Time {
+	return slotTimeOn(day, s.EndTime)
+}
+
+func slotTimeOn(day time.Time, clock string) time.Time {
+	t, _ := time.Parse(PickupSlotTimeLayout, clock)
+	year, month, date := day.Date()
+	return time.Date(year, month, date, t.Hour(), t.Minute(), 0, 0, day.Location())
+}
@@ -14,6 +14,7 @@ type Product struct {
 	Variant     string    `gorm:"size:20;not null"`
 	Price       int       `gorm:"not null;default:0"`
 	Stock       int       `gorm:"not null;default:0"`
+	Reserved    int       `gorm:"not null;default:0"` // stok yang di-hold order pending
 	Description string    `gorm:"type:text"`
 	Star        float64   `gorm:"size:20;default:5.0"`
 	ImageURL    string    `gorm:"size:255;not null"`
@@ -21,6 +22,7 @@ type Product struct {
 	Category    Category  `gorm:"foreignKey:CategoryID"` // relasi
 	SpecialType string    `gorm:"size:50;default:null"`  // contoh: "special1", "special2"
 	IsSpecial   bool      `gorm:"default:false"`         // flag khusus
+	IsActive    bool      `gorm:"default:true"`          // produk nonaktif tidak bisa diorder
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -0,0 +1,60 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// jenis selisih antara order kita dengan transaksi di payment provider
+const (
+	MismatchPaidButPending     = "paid_but_pending"    // di Midtrans sudah paid, order masih pending
+	MismatchStatus             = "status_mismatch"     // status final berbeda, perlu dicek manual
+	MismatchAmount             = "amount_mismatch"     // gross amount berbeda dengan amount order
+	MismatchUnknownTransaction = "unknown_transaction" // order sudah dibayar tapi transaksi tidak dikenal Midtrans
+	MismatchMissingPaymentLog  = "missing_payment_log" // status terakhir di Midtrans belum tercatat di payment log
+)
+
+const ActorReconciliation = "system:reconciliation"
+
+const (
+	ReconciliationStatusQueued  = "queued"
+	ReconciliationStatusRunning = "running"
+	ReconciliationStatusDone    = "done"
+	ReconciliationStatusFailed  = "failed" // gagal di tengah jalan / terhenti karena shutdown, item yang sudah tercatat tetap disimpan
+)
+
+// ReconciliationRun satu kali proses rekonsiliasi untuk rentang tanggal order (timezone toko)
+type ReconciliationRun struct {
+	ID            uuid.UUID            `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	DateFrom      string               `gorm:"size:10;not null"` // YYYY-MM-DD
+	DateTo        string               `gorm:"size:10;not null"`
+	Trigger       string               `gorm:"size:100;not null"` // ex: cms:<user_id>, system:reconciliation
+	AutoFix       bool                 `gorm:"not null;default:false"`
+	Status        string               `gorm:"size:20;not null;default:'done'"` // queued, running, done, failed (default untuk run lama yang selalu sinkron)
+	Error         string               `gorm:"size:255"`
+	TotalChecked  int                  `gorm:"not null;default:0"`
+	TotalMismatch int                  `gorm:"not null;default:0"`
+	TotalFixed    int                  `gorm:"not null;default:0"`
+	TotalError    int                  `gorm:"not null;default:0"` // gagal cek status ke provider
+	FinishedAt    *time.Time           `gorm:"default:null"`
+	Items         []ReconciliationItem `gorm:"foreignKey:RunID"`
+	CreatedAt     time.Time
+}
+
+type ReconciliationItem struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	RunID          uuid.UUID  `gorm:"type:uuid;index;not null"`
+	OrderID        *uuid.UUID `gorm:"type:uuid;default:null"` // salah satu terisi: order atau top up wallet
+	TopUpID        *uuid.UUID `gorm:"type:uuid;default:null"`
+	InvoiceNumber  string     `gorm:"size:50;not null"`
+	Type           string     `gorm:"size:30;not null"`
+	OrderStatus    string     `gorm:"size:20"`
+	ProviderStatus string     `gorm:"size:20"` // transaction_status mentah dari Midtrans
+	OrderAmount    int64      `gorm:"not null;default:0"`
+	ProviderAmount int64      `gorm:"not null;default:0"`
+	LastLogStatus  string     `gorm:"size:20"`
+	Fixed          bool       `gorm:"not null;default:false"`
+	Note           string     `gorm:"size:255"`
+	CreatedAt      time.Time
+}
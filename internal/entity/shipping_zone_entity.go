@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Implement Searchable
+func (ShippingZone) SearchFields() []string {
+	return []string{"name", "city", "postal_code_prefix"}
+}
+
+// ShippingZone area pengiriman, dicocokkan dengan kota dan / atau prefix kode pos alamat customer
+type ShippingZone struct {
+	ID               uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name             string    `gorm:"size:100;not null"`
+	City             string    `gorm:"size:100"`           // kosong = semua kota
+	PostalCodePrefix string    `gorm:"size:10"`            // ex: "401" untuk 401xx, kosong = semua kode pos
+	Fee              int64     `gorm:"not null;default:0"` // ongkir
+	MinOrder         int64     `gorm:"not null;default:0"` // minimal subtotal supaya bisa delivery
+	FreeThreshold    int64     `gorm:"not null;default:0"` // subtotal >= ini gratis ongkir, 0 = tidak ada
+	IsActive         bool      `gorm:"not null"`
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+func (z *ShippingZone) Matches(city, postalCode string) bool {
+	if z.City != "" && !strings.EqualFold(strings.TrimSpace(city), z.City) {
+		return false
+	}
+	if z.PostalCodePrefix != "" && !strings.HasPrefix(strings.TrimSpace(postalCode), z.PostalCodePrefix) {
+		return false
+	}
+	return z.City != "" || z.PostalCodePrefix != ""
+}
+
+// Specificity zone yang lebih spesifik menang kalau ada beberapa yang cocok, kode pos lebih spesifik dari kota
+func (z *ShippingZone) Specificity() int {
+	score := len(z.PostalCodePrefix) * 2
+	if z.City != "" {
+		score++
+	}
+	return score
+}
+
+// FeeFor ongkir untuk subtotal tertentu, sudah memperhitungkan gratis ongkir
+func (z *ShippingZone) FeeFor(subtotal int64) int64 {
+	if z.FreeThreshold > 0 && subtotal >= z.FreeThreshold {
+		return 0
+	}
+	return z.Fee
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	VoucherTypePercentage = "percentage"
+	VoucherTypeFixed      = "fixed"
+)
+
+const (
+	RedemptionStatusApplied  = "applied"  // ikut order yang masih jalan / sudah dibayar
+	RedemptionStatusReleased = "released" // order gagal / expired / cancel, kuota dikembalikan
+)
+
+// Implement Searchable
+func (Voucher) SearchFields() []string {
+	return []string{"code", "name"}
+}
+
+type Voucher struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Code             string     `gorm:"size:50;not null;unique"` // selalu uppercase
+	Name             string     `gorm:"size:100;not null"`
+	Description      string     `gorm:"type:text"`
+	Type             string     `gorm:"size:20;not null"`   // percentage, fixed
+	Value            int64      `gorm:"not null"`           // persen (1-100) atau nominal rupiah
+	MinSpend         int64      `gorm:"not null;default:0"` // minimal subtotal item yang eligible
+	MaxDiscount      int64      `gorm:"not null;default:0"` // 0 = tanpa batas
+	UsageLimit       int        `gorm:"not null;default:0"` // kuota global, 0 = tanpa batas
+	PerCustomerLimit int        `gorm:"not null;default:0"` // 0 = tanpa batas
+	UsedCount        int        `gorm:"not null;default:0"` // redemption yang masih applied
+	StartsAt         *time.Time `gorm:"default:null"`
+	EndsAt           *time.Time `gorm:"default:null"`
+	IsActive         bool       `gorm:"not null"`                     // tanpa default, supaya voucher nonaktif (false) ikut tersimpan
+	Products         []Product  `gorm:"many2many:voucher_products"`   // kosong = semua produk
+	Categories       []Category `gorm:"many2many:voucher_categories"` // kosong = semua kategori
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+}
+
+// VoucherRedemption pemakaian voucher per order, dibuat di transaksi yang sama dengan order
+type VoucherRedemption struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	VoucherID  uuid.UUID `gorm:"type:uuid;not null;index"`
+	OrderID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	CustomerID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Code       string    `gorm:"size:50;not null"`
+	Amount     int64     `gorm:"not null"`
+	Status     string    `gorm:"size:20;not null;default:'applied'"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// IsUsable voucher aktif dan masih dalam masa berlaku
+func (v *Voucher) IsUsable(now time.Time) bool {
+	if !v.IsActive {
+		return false
+	}
+	if v.StartsAt != nil && now.Before(*v.StartsAt) {
+		return false
+	}
+	if v.EndsAt != nil && !now.Before(*v.EndsAt) {
+		return false
+	}
+	return true
+}
+
+// IsRestricted voucher hanya berlaku untuk produk / kategori tertentu
+func (v *Voucher) IsRestricted() bool {
+	return len(v.Products) > 0 || len(v.Categories) > 0
+}
+
+// Applies cek apakah produk masuk restriksi voucher
+func (v *Voucher) Applies(product *Product) bool {
+	if !v.IsRestricted() {
+		return true
+	}
+	for _, p := range v.Products {
+		if p.ID == product.ID {
+			return true
+		}
+	}
+	for _, c := range v.Categories {
+		if c.ID == product.CategoryID {
+			return true
+		}
+	}
+	return false
+}
+
+// DiscountFor hitung potongan dari subtotal item yang eligible
+func (v *Voucher) DiscountFor(eligible int64) int64 {
+	discount := v.Value
+	if v.Type == VoucherTypePercentage {
+		discount = eligible * v.Value / 100
+	}
+	if v.MaxDiscount > 0 && discount > v.MaxDiscount {
+		discount = v.MaxDiscount
+	}
+	return min(discount, eligible)
+}
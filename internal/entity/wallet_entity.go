@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentMethodWallet bayar order pakai saldo wallet, tanpa charge ke Midtrans
+const PaymentMethodWallet = "wallet"
+
+const (
+	WalletEntryCredit = "credit" // saldo bertambah
+	WalletEntryDebit  = "debit"  // saldo berkurang
+)
+
+// sumber mutasi wallet
+const (
+	WalletSourceTopUp  = "topup"
+	WalletSourceOrder  = "order"
+	WalletSourceRefund = "refund"
+)
+
+// akun lawan setiap mutasi, jadi tiap entry bisa dibaca seperti jurnal double-entry
+const (
+	WalletAccountPaymentGateway = "payment_gateway" // dana masuk dari Midtrans
+	WalletAccountSales          = "sales"           // pembayaran order
+	WalletAccountRefund         = "refund"          // pengembalian dana order
+)
+
+const (
+	TopUpStatusPending   = "pending"
+	TopUpStatusPaid      = "paid"
+	TopUpStatusFailed    = "failed"
+	TopUpStatusExpired   = "expired"
+	TopUpStatusCancelled = "cancelled"
+)
+
+// Wallet saldo per customer, Balance selalu sama dengan total credit - debit di WalletEntry
+type Wallet struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CustomerID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex"`
+	Balance    int64     `gorm:"not null;default:0"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// WalletEntry ledger wallet, tidak pernah diubah / dihapus. Kombinasi source + reference + type unik
+// supaya mutasi yang sama (ex: notifikasi top up dikirim ulang) tidak tercatat dua kali.
+type WalletEntry struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	WalletID       uuid.UUID `gorm:"type:uuid;not null;index"`
+	Type           string    `gorm:"size:10;not null;uniqueIndex:idx_wallet_entry_reference"` // credit, debit
+	Amount         int64     `gorm:"not null"`                                                // selalu positif
+	BalanceAfter   int64     `gorm:"not null"`
+	Source         string    `gorm:"size:20;not null;uniqueIndex:idx_wallet_entry_reference"` // topup, order, refund
+	ReferenceID    string    `gorm:"size:100;not null;uniqueIndex:idx_wallet_entry_reference"`
+	CounterAccount string    `gorm:"size:30;not null"` // payment_gateway, sales, refund
+	Description    string    `gorm:"size:255"`
+	Actor          string    `gorm:"size:100;not null"`
+	CreatedAt      time.Time
+}
+
+// WalletTopUp isi saldo lewat Midtrans, saldo baru masuk setelah notifikasi settlement
+type WalletTopUp struct {
+	ID            uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CustomerID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	InvoiceNumber string     `gorm:"size:50;unique;not null"` // ex: TOP-20250908-0001
+	Amount        int64      `gorm:"not null"`
+	Status        string     `gorm:"size:20;not null;default:'pending'"`
+	PaymentMethod string     `gorm:"size:50"`
+	PaymentType   string     `gorm:"size:50"`
+	TransactionID string     `gorm:"size:100"`
+	CheckoutMode  string     `gorm:"size:10;not null;default:'core'"`
+	SnapToken     string     `gorm:"size:100"`
+	RedirectURL   string     `gorm:"size:255"`
+	QRString      string     `gorm:"type:text"`
+	QRURL         string     `gorm:"size:255"`
+	DeeplinkURL   string     `gorm:"size:255"`
+	VANumber      string     `gorm:"size:50"`
+	VABank        string     `gorm:"size:20"`
+	ExpiredAt     *time.Time `gorm:"default:null"`
+	PaidAt        *time.Time `gorm:"default:null"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
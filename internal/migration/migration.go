@@ -16,6 +16,21 @@ func Run(db *gorm.DB, log *logrus.Logger) {
 		&entity.Order{},
 		&entity.OrderItem{},
 		&entity.PaymentLog{},
+		&entity.OrderStatusHistory{},
+		&entity.Refund{},
+		&entity.RefundItem{},
+		&entity.InvoiceCounter{},
+		&entity.ReconciliationRun{},
+		&entity.ReconciliationItem{},
+		&entity.Voucher{},
+		&entity.VoucherRedemption{},
+		&entity.ShippingZone{},
+		&entity.PickupSlot{},
+		&entity.Wallet{},
+		&entity.WalletEntry{},
+		&entity.WalletTopUp{},
+		&entity.LoyaltyAccount{},
+		&entity.LoyaltyTransaction{},
 	)
 
 	if err != nil {
@@ -0,0 +1,55 @@
+package converter
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
+
+// OrderResponseToKitchen versi ringkas order untuk layar barista, tanpa info pembayaran.
+// Pre-order yang belum masuk prep window tidak punya queue (belum tampil di layar).
+func OrderResponseToKitchen(order *model.OrderResponse) *model.KitchenOrderResponse {
+	response := &model.KitchenOrderResponse{
+		ID:              order.ID,
+		InvoiceNumber:   order.InvoiceNumber,
+		Status:          order.Status,
+		Queue:           KitchenQueue(order.Status),
+		FulfillmentType: order.FulfillmentType,
+		PickupAt:        order.PickupAt,
+		ScheduledFor:    order.ScheduledFor,
+		Notes:           order.Notes,
+		Items:           []model.KitchenItemResponse{},
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+	}
+	if order.ScheduledFor != "" && order.KitchenReleasedAt == "" {
+		response.Queue = ""
+	}
+
+	for _, item := range order.Items {
+		qty := item.Qty - item.RefundedQty
+		if qty <= 0 {
+			continue
+		}
+		response.Items = append(response.Items, model.KitchenItemResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Qty:         qty,
+		})
+	}
+
+	return response
+}
+
+// KitchenQueue nama antrian barista untuk status order, kosong kalau order tidak tampil di layar
+func KitchenQueue(status string) string {
+	switch status {
+	case entity.OrderStatusPaid:
+		return model.KitchenQueueNew
+	case entity.OrderStatusPreparing:
+		return model.KitchenQueuePreparing
+	case entity.OrderStatusReady:
+		return model.KitchenQueueReady
+	default:
+		return ""
+	}
+}
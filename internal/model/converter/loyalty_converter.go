@@ -0,0 +1,39 @@
+package converter
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
+
+func LoyaltyToResponse(account *entity.LoyaltyAccount, pointValue int64) *model.LoyaltyResponse {
+	response := &model.LoyaltyResponse{
+		CustomerID: account.CustomerID.String(),
+		Balance:    account.Balance,
+		PointValue: pointValue,
+	}
+	// akun yang belum pernah dapat poin belum punya row di database
+	if !account.UpdatedAt.IsZero() {
+		response.UpdatedAt = account.UpdatedAt.String()
+	}
+	return response
+}
+
+func LoyaltyTransactionToResponse(transaction *entity.LoyaltyTransaction) *model.LoyaltyTransactionResponse {
+	response := &model.LoyaltyTransactionResponse{
+		ID:           transaction.ID.String(),
+		Type:         transaction.Type,
+		Points:       transaction.Points,
+		BalanceAfter: transaction.BalanceAfter,
+		Remaining:    transaction.Remaining,
+		Reason:       transaction.Reason,
+		Actor:        transaction.Actor,
+		CreatedAt:    transaction.CreatedAt.String(),
+	}
+	if transaction.ExpiresAt != nil {
+		response.ExpiresAt = transaction.ExpiresAt.String()
+	}
+	if transaction.OrderID != nil {
+		response.OrderID = transaction.OrderID.String()
+	}
+	return response
+}
@@ -0,0 +1,192 @@
+package converter
+
+import (
+	"encoding/json"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
+
+func OrderToResponse(order *entity.Order) *model.OrderResponse {
+	response := &model.OrderResponse{
+		ID:              order.ID.String(),
+		CustomerID:      order.UserID.String(),
+		InvoiceNumber:   order.InvoiceNumber,
+		Status:          order.Status,
+		Amount:          order.Amount,
+		Breakdown:       OrderToPriceBreakdown(order),
+		VoucherCode:     order.VoucherCode,
+		PointsRedeemed:  order.PointsRedeemed,
+		PointsEarned:    order.PointsEarned,
+		RefundedAmount:  order.RefundedAmount,
+		PaymentMethod:   order.PaymentMethod,
+		PaymentType:     order.PaymentType,
+		TransactionID:   order.TransactionID,
+		RedirectURL:     order.RedirectURL,
+		Notes:           order.Notes,
+		FulfillmentType: order.FulfillmentType,
+		ShippingAddress: order.ShippingAddr,
+		StockStatus:     order.StockStatus,
+		CreatedAt:       order.CreatedAt.String(),
+		UpdatedAt:       order.UpdatedAt.String(),
+	}
+
+	if order.ExpiredAt != nil {
+		response.ExpiredAt = order.ExpiredAt.String()
+	}
+	if order.PickupSlotID != nil {
+		response.PickupSlotID = order.PickupSlotID.String()
+	}
+	if order.PickupAt != nil {
+		response.PickupAt = order.PickupAt.String()
+	}
+	if order.ScheduledFor != nil {
+		response.ScheduledFor = order.ScheduledFor.String()
+	}
+	if order.KitchenReleasedAt != nil {
+		response.KitchenReleasedAt = order.KitchenReleasedAt.String()
+	}
+
+	for _, item := range order.OrderItems {
+		response.Items = append(response.Items, *OrderItemToResponse(&item))
+	}
+	for _, log := range order.PaymentLogs {
+		response.PaymentLogs = append(response.PaymentLogs, *PaymentLogToResponse(&log))
+	}
+	for _, history := range order.Histories {
+		response.Histories = append(response.Histories, *OrderStatusHistoryToResponse(&history))
+	}
+	for _, refund := range order.Refunds {
+		response.Refunds = append(response.Refunds, *RefundToResponse(&refund))
+	}
+
+	return response
+}
+
+// OrderToCustomerResponse versi detail order untuk customer, tanpa payment log & audit trail internal
+func OrderToCustomerResponse(order *entity.Order) *model.OrderResponse {
+	response := OrderToResponse(order)
+	response.StockStatus = ""
+	response.KitchenReleasedAt = ""
+	response.PaymentLogs = nil
+	response.Histories = nil
+	response.Refunds = nil
+	response.Payment = OrderToPaymentInstruction(order)
+	return response
+}
+
+func OrderToCreateResponse(order *entity.Order) *model.CreateOrderResponse {
+	response := &model.CreateOrderResponse{
+		ID:              order.ID.String(),
+		InvoiceNumber:   order.InvoiceNumber,
+		Status:          order.Status,
+		Amount:          order.Amount,
+		Breakdown:       OrderToPriceBreakdown(order),
+		VoucherCode:     order.VoucherCode,
+		PointsRedeemed:  order.PointsRedeemed,
+		FulfillmentType: order.FulfillmentType,
+		Payment:         OrderToPaymentInstruction(order),
+	}
+	if order.ExpiredAt != nil {
+		response.ExpiredAt = order.ExpiredAt.String()
+	}
+	if order.PickupAt != nil {
+		response.PickupAt = order.PickupAt.String()
+	}
+	if order.ScheduledFor != nil {
+		response.ScheduledFor = order.ScheduledFor.String()
+	}
+	return response
+}
+
+func OrderToPriceBreakdown(order *entity.Order) *model.PriceBreakdownResponse {
+	return &model.PriceBreakdownResponse{
+		Subtotal:       order.Subtotal,
+		Discount:       order.DiscountAmount,
+		PointsDiscount: order.PointsDiscount,
+		ServiceCharge:  order.ServiceCharge,
+		ServiceRate:    float64(order.ServiceRate) / 100,
+		Tax:            order.TaxAmount,
+		TaxRate:        float64(order.TaxRate) / 100,
+		TaxInclusive:   order.TaxInclusive,
+		DeliveryFee:    order.DeliveryFee,
+		Rounding:       order.RoundingAmount,
+		Total:          order.Amount,
+	}
+}
+
+func OrderToPaymentInstruction(order *entity.Order) *model.PaymentInstructionResponse {
+	instruction := &model.PaymentInstructionResponse{
+		CheckoutMode: order.CheckoutMode,
+		Method:       order.PaymentMethod,
+		Type:         order.PaymentType,
+		QRString:     order.QRString,
+		QRURL:        order.QRURL,
+		DeeplinkURL:  order.DeeplinkURL,
+		VANumber:     order.VANumber,
+		VABank:       order.VABank,
+		SnapToken:    order.SnapToken,
+		RedirectURL:  order.RedirectURL,
+	}
+	if order.ExpiredAt != nil {
+		instruction.ExpiredAt = order.ExpiredAt.String()
+	}
+	return instruction
+}
+
+func OrderItemToResponse(item *entity.OrderItem) *model.OrderItemResponse {
+	return &model.OrderItemResponse{
+		ID:             item.ID.String(),
+		ProductID:      item.ProductID.String(),
+		ProductName:    item.ProductName,
+		Qty:            item.Qty,
+		Price:          item.Price,
+		Subtotal:       item.Subtotal,
+		RefundedQty:    item.RefundedQty,
+		RefundedAmount: item.RefundedAmount,
+	}
+}
+
+func PaymentLogToResponse(log *entity.PaymentLog) *model.PaymentLogResponse {
+	response := &model.PaymentLogResponse{
+		ID:            log.ID.String(),
+		TransactionID: log.MidtransTransaction,
+		Status:        log.Status,
+		CreatedAt:     log.CreatedAt.String(),
+	}
+	if len(log.RawResponse) > 0 {
+		response.RawResponse = json.RawMessage(log.RawResponse)
+	}
+	return response
+}
+
+func OrderStatusHistoryToResponse(history *entity.OrderStatusHistory) *model.OrderStatusHistoryResponse {
+	return &model.OrderStatusHistoryResponse{
+		FromStatus: history.FromStatus,
+		ToStatus:   history.ToStatus,
+		Reason:     history.Reason,
+		Actor:      history.Actor,
+		CreatedAt:  history.CreatedAt.String(),
+	}
+}
+
+func RefundToResponse(refund *entity.Refund) *model.RefundResponse {
+	response := &model.RefundResponse{
+		ID:          refund.ID.String(),
+		RefundKey:   refund.RefundKey,
+		Amount:      refund.Amount,
+		Status:      refund.Status,
+		Destination: refund.Destination,
+		Reason:      refund.Reason,
+		Actor:       refund.Actor,
+		CreatedAt:   refund.CreatedAt.String(),
+	}
+	for _, item := range refund.Items {
+		response.Items = append(response.Items, model.RefundItemResponse{
+			OrderItemID: item.OrderItemID.String(),
+			Qty:         item.Qty,
+			Amount:      item.Amount,
+		})
+	}
+	return response
+}
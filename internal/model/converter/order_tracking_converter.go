@@ -0,0 +1,42 @@
+package converter
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/realtime"
+)
+
+// OrderToStatusEvent status order saat ini untuk event snapshot stream customer
+func OrderToStatusEvent(order *entity.Order) *model.OrderStatusEventResponse {
+	response := &model.OrderStatusEventResponse{
+		OrderID:         order.ID.String(),
+		InvoiceNumber:   order.InvoiceNumber,
+		Status:          order.Status,
+		FulfillmentType: order.FulfillmentType,
+		OccurredAt:      order.UpdatedAt.String(),
+	}
+	if order.PickupAt != nil {
+		response.PickupAt = order.PickupAt.String()
+	}
+	if order.ExpiredAt != nil {
+		response.ExpiredAt = order.ExpiredAt.String()
+	}
+	return response
+}
+
+// OrderEventToStatusEvent versi event order untuk customer, hanya status tanpa data pembayaran & stok
+func OrderEventToStatusEvent(event *realtime.OrderEvent) *model.OrderStatusEventResponse {
+	response := &model.OrderStatusEventResponse{
+		OrderID:    event.OrderID,
+		FromStatus: event.FromStatus,
+		Status:     event.Status,
+		OccurredAt: event.OccurredAt.String(),
+	}
+	if event.Order != nil {
+		response.InvoiceNumber = event.Order.InvoiceNumber
+		response.FulfillmentType = event.Order.FulfillmentType
+		response.PickupAt = event.Order.PickupAt
+		response.ExpiredAt = event.Order.ExpiredAt
+	}
+	return response
+}
@@ -0,0 +1,19 @@
+package converter
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
+
+func PickupSlotToResponse(slot *entity.PickupSlot) *model.PickupSlotResponse {
+	return &model.PickupSlotResponse{
+		ID:        slot.ID.String(),
+		Name:      slot.Name,
+		StartTime: slot.StartTime,
+		EndTime:   slot.EndTime,
+		Capacity:  slot.Capacity,
+		IsActive:  slot.IsActive,
+		CreatedAt: slot.CreatedAt.String(),
+		UpdatedAt: slot.UpdatedAt.String(),
+	}
+}
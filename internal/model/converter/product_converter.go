@@ -14,9 +14,11 @@ func ProductToResponse(product *entity.Product) *model.ProductResponse {
 		Variant:     product.Variant,
 		Price:       product.Price,
 		Stock:       product.Stock,
+		Reserved:    product.Reserved,
 		Description: product.Description,
 		ImageURL:    product.ImageURL,
 		CategoryID:  product.CategoryID,
+		IsActive:    product.IsActive,
 		CreatedAt:   product.CreatedAt.String(),
 		UpdatedAt:   product.UpdatedAt.String(),
 	}
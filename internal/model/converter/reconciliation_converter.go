@@ -0,0 +1,50 @@
+package converter
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
+
+func ReconciliationRunToResponse(run *entity.ReconciliationRun) *model.ReconciliationRunResponse {
+	response := &model.ReconciliationRunResponse{
+		ID:            run.ID.String(),
+		DateFrom:      run.DateFrom,
+		DateTo:        run.DateTo,
+		Trigger:       run.Trigger,
+		AutoFix:       run.AutoFix,
+		Status:        run.Status,
+		Error:         run.Error,
+		TotalChecked:  run.TotalChecked,
+		TotalMismatch: run.TotalMismatch,
+		TotalFixed:    run.TotalFixed,
+		TotalError:    run.TotalError,
+		CreatedAt:     run.CreatedAt.String(),
+	}
+	if run.FinishedAt != nil {
+		response.FinishedAt = run.FinishedAt.String()
+	}
+
+	for _, item := range run.Items {
+		itemResponse := model.ReconciliationItemResponse{
+			ID:             item.ID.String(),
+			InvoiceNumber:  item.InvoiceNumber,
+			Type:           item.Type,
+			OrderStatus:    item.OrderStatus,
+			ProviderStatus: item.ProviderStatus,
+			OrderAmount:    item.OrderAmount,
+			ProviderAmount: item.ProviderAmount,
+			LastLogStatus:  item.LastLogStatus,
+			Fixed:          item.Fixed,
+			Note:           item.Note,
+		}
+		if item.OrderID != nil {
+			itemResponse.OrderID = item.OrderID.String()
+		}
+		if item.TopUpID != nil {
+			itemResponse.TopUpID = item.TopUpID.String()
+		}
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	return response
+}
@@ -0,0 +1,21 @@
+package converter
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
+
+func ShippingZoneToResponse(zone *entity.ShippingZone) *model.ShippingZoneResponse {
+	return &model.ShippingZoneResponse{
+		ID:               zone.ID.String(),
+		Name:             zone.Name,
+		City:             zone.City,
+		PostalCodePrefix: zone.PostalCodePrefix,
+		Fee:              zone.Fee,
+		MinOrder:         zone.MinOrder,
+		FreeThreshold:    zone.FreeThreshold,
+		IsActive:         zone.IsActive,
+		CreatedAt:        zone.CreatedAt.String(),
+		UpdatedAt:        zone.UpdatedAt.String(),
+	}
+}
@@ -0,0 +1,42 @@
+package converter
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
+
+func VoucherToResponse(voucher *entity.Voucher) *model.VoucherResponse {
+	response := &model.VoucherResponse{
+		ID:               voucher.ID.String(),
+		Code:             voucher.Code,
+		Name:             voucher.Name,
+		Description:      voucher.Description,
+		Type:             voucher.Type,
+		Value:            voucher.Value,
+		MinSpend:         voucher.MinSpend,
+		MaxDiscount:      voucher.MaxDiscount,
+		UsageLimit:       voucher.UsageLimit,
+		PerCustomerLimit: voucher.PerCustomerLimit,
+		UsedCount:        voucher.UsedCount,
+		IsActive:         voucher.IsActive,
+		ProductIDs:       make([]string, len(voucher.Products)),
+		CategoryIDs:      make([]string, len(voucher.Categories)),
+		CreatedAt:        voucher.CreatedAt.String(),
+		UpdatedAt:        voucher.UpdatedAt.String(),
+	}
+
+	if voucher.StartsAt != nil {
+		response.StartsAt = voucher.StartsAt.String()
+	}
+	if voucher.EndsAt != nil {
+		response.EndsAt = voucher.EndsAt.String()
+	}
+	for i, product := range voucher.Products {
+		response.ProductIDs[i] = product.ID.String()
+	}
+	for i, category := range voucher.Categories {
+		response.CategoryIDs[i] = category.ID.String()
+	}
+
+	return response
+}
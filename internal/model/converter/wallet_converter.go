@@ -0,0 +1,60 @@
+package converter
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
+
+func WalletToResponse(wallet *entity.Wallet) *model.WalletResponse {
+	response := &model.WalletResponse{
+		CustomerID: wallet.CustomerID.String(),
+		Balance:    wallet.Balance,
+	}
+	// wallet yang belum pernah dipakai belum punya row di database
+	if !wallet.UpdatedAt.IsZero() {
+		response.UpdatedAt = wallet.UpdatedAt.String()
+	}
+	return response
+}
+
+func WalletEntryToResponse(entry *entity.WalletEntry) *model.WalletEntryResponse {
+	return &model.WalletEntryResponse{
+		ID:             entry.ID.String(),
+		Type:           entry.Type,
+		Amount:         entry.Amount,
+		BalanceAfter:   entry.BalanceAfter,
+		Source:         entry.Source,
+		ReferenceID:    entry.ReferenceID,
+		CounterAccount: entry.CounterAccount,
+		Description:    entry.Description,
+		Actor:          entry.Actor,
+		CreatedAt:      entry.CreatedAt.String(),
+	}
+}
+
+func WalletTopUpToResponse(topUp *entity.WalletTopUp) *model.WalletTopUpResponse {
+	response := &model.WalletTopUpResponse{
+		ID:            topUp.ID.String(),
+		InvoiceNumber: topUp.InvoiceNumber,
+		Amount:        topUp.Amount,
+		Status:        topUp.Status,
+		CreatedAt:     topUp.CreatedAt.String(),
+		Payment: &model.PaymentInstructionResponse{
+			CheckoutMode: topUp.CheckoutMode,
+			Method:       topUp.PaymentMethod,
+			Type:         topUp.PaymentType,
+			QRString:     topUp.QRString,
+			QRURL:        topUp.QRURL,
+			DeeplinkURL:  topUp.DeeplinkURL,
+			VANumber:     topUp.VANumber,
+			VABank:       topUp.VABank,
+			SnapToken:    topUp.SnapToken,
+			RedirectURL:  topUp.RedirectURL,
+		},
+	}
+	if topUp.ExpiredAt != nil {
+		response.ExpiredAt = topUp.ExpiredAt.String()
+		response.Payment.ExpiredAt = topUp.ExpiredAt.String()
+	}
+	return response
+}
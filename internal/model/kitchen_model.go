@@ -0,0 +1,51 @@
+package model
+
+const (
+	KitchenQueueNew       = "new"
+	KitchenQueuePreparing = "preparing"
+	KitchenQueueReady     = "ready"
+)
+
+const (
+	KitchenActionUpsert = "upsert" // order masuk / pindah antrian
+	KitchenActionRemove = "remove" // order keluar dari layar (completed, refunded)
+)
+
+// KitchenQueueResponse antrian order di layar barista, new = sudah dibayar tapi belum mulai dibuat
+type KitchenQueueResponse struct {
+	New       []KitchenOrderResponse `json:"new"`
+	Preparing []KitchenOrderResponse `json:"preparing"`
+	Ready     []KitchenOrderResponse `json:"ready"`
+}
+
+type KitchenOrderResponse struct {
+	ID              string                `json:"id"`
+	InvoiceNumber   string                `json:"invoice_number"`
+	Status          string                `json:"status"`
+	Queue           string                `json:"queue,omitempty"`
+	FulfillmentType string                `json:"fulfillment_type"`
+	PickupAt        string                `json:"pickup_at,omitempty"`
+	ScheduledFor    string                `json:"scheduled_for,omitempty"` // pre-order, harus siap jam ini
+	Notes           string                `json:"notes"`
+	Items           []KitchenItemResponse `json:"items"`
+	CreatedAt       string                `json:"created_at,omitempty"`
+	UpdatedAt       string                `json:"updated_at,omitempty"`
+}
+
+type KitchenItemResponse struct {
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Qty         int    `json:"qty"` // sudah dikurangi qty yang di-refund
+}
+
+// KitchenEventResponse data event "order" di stream layar barista
+type KitchenEventResponse struct {
+	Action string                `json:"action"`
+	Order  *KitchenOrderResponse `json:"order"`
+}
+
+// MoveKitchenOrderRequest pindahkan order ke antrian berikutnya, preparing -> ready -> completed (sudah diambil)
+type MoveKitchenOrderRequest struct {
+	Status string `json:"status" validate:"required,oneof=preparing ready completed"`
+	Reason string `json:"reason" validate:"omitempty,max=255"`
+}
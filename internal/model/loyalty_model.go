@@ -0,0 +1,27 @@
+package model
+
+type LoyaltyResponse struct {
+	CustomerID string `json:"customer_id"`
+	Balance    int64  `json:"balance"`
+	PointValue int64  `json:"point_value"` // rupiah per poin saat redeem
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+type LoyaltyTransactionResponse struct {
+	ID           string `json:"id"`
+	Type         string `json:"type"`
+	Points       int64  `json:"points"`
+	BalanceAfter int64  `json:"balance_after"`
+	Remaining    int64  `json:"remaining,omitempty"`
+	ExpiresAt    string `json:"expires_at,omitempty"`
+	OrderID      string `json:"order_id,omitempty"`
+	Reason       string `json:"reason"`
+	Actor        string `json:"actor,omitempty"`
+	CreatedAt    string `json:"created_at,omitempty"`
+}
+
+// AdjustLoyaltyRequest points negatif untuk mengurangi poin
+type AdjustLoyaltyRequest struct {
+	Points int64  `json:"points" validate:"required,ne=0"`
+	Reason string `json:"reason" validate:"required,max=255"`
+}
@@ -1,21 +1,239 @@
 package model
 
+import "encoding/json"
+
+type OrderResponse struct {
+	ID                string                       `json:"id"`
+	CustomerID        string                       `json:"customer_id"`
+	InvoiceNumber     string                       `json:"invoice_number"`
+	Status            string                       `json:"status"`
+	Amount            int64                        `json:"amount"`
+	Breakdown         *PriceBreakdownResponse      `json:"breakdown"`
+	VoucherCode       string                       `json:"voucher_code,omitempty"`
+	PointsRedeemed    int64                        `json:"points_redeemed,omitempty"`
+	PointsEarned      int64                        `json:"points_earned,omitempty"`
+	RefundedAmount    int64                        `json:"refunded_amount"`
+	PaymentMethod     string                       `json:"payment_method"`
+	PaymentType       string                       `json:"payment_type"`
+	TransactionID     string                       `json:"transaction_id"`
+	RedirectURL       string                       `json:"redirect_url,omitempty"`
+	ExpiredAt         string                       `json:"expired_at,omitempty"`
+	Notes             string                       `json:"notes"`
+	FulfillmentType   string                       `json:"fulfillment_type"`
+	PickupSlotID      string                       `json:"pickup_slot_id,omitempty"`
+	PickupAt          string                       `json:"pickup_at,omitempty"`
+	ScheduledFor      string                       `json:"scheduled_for,omitempty"`
+	KitchenReleasedAt string                       `json:"kitchen_released_at,omitempty"`
+	ShippingAddress   string                       `json:"shipping_address"`
+	StockStatus       string                       `json:"stock_status,omitempty"`
+	Payment           *PaymentInstructionResponse  `json:"payment,omitempty"`
+	Items             []OrderItemResponse          `json:"items,omitempty"`
+	PaymentLogs       []PaymentLogResponse         `json:"payment_logs,omitempty"`
+	Histories         []OrderStatusHistoryResponse `json:"histories,omitempty"`
+	Refunds           []RefundResponse             `json:"refunds,omitempty"`
+	CreatedAt         string                       `json:"created_at,omitempty"`
+	UpdatedAt         string                       `json:"updated_at,omitempty"`
+}
+
+// PaymentInstructionResponse info yang dibutuhkan customer untuk menyelesaikan pembayaran,
+// field yang terisi tergantung metode: qr (qris/gopay), deeplink (e-wallet), va (bank transfer)
+type PaymentInstructionResponse struct {
+	CheckoutMode string `json:"checkout_mode"`
+	Method       string `json:"method"`
+	Type         string `json:"type"`
+	QRString     string `json:"qr_string,omitempty"`
+	QRURL        string `json:"qr_url,omitempty"`
+	DeeplinkURL  string `json:"deeplink_url,omitempty"`
+	VANumber     string `json:"va_number,omitempty"`
+	VABank       string `json:"va_bank,omitempty"`
+	SnapToken    string `json:"snap_token,omitempty"`
+	RedirectURL  string `json:"redirect_url,omitempty"`
+	ExpiredAt    string `json:"expired_at,omitempty"`
+}
+
+// PriceBreakdownResponse rincian harga order, total = subtotal - discount + service_charge + tax (exclusive) + delivery_fee + rounding.
+// discount sudah termasuk points_discount.
+type PriceBreakdownResponse struct {
+	Subtotal       int64   `json:"subtotal"`
+	Discount       int64   `json:"discount"`
+	PointsDiscount int64   `json:"points_discount"`
+	ServiceCharge  int64   `json:"service_charge"`
+	ServiceRate    float64 `json:"service_rate"` // persen
+	Tax            int64   `json:"tax"`
+	TaxRate        float64 `json:"tax_rate"` // persen
+	TaxInclusive   bool    `json:"tax_inclusive"`
+	DeliveryFee    int64   `json:"delivery_fee"`
+	Rounding       int64   `json:"rounding"`
+	Total          int64   `json:"total"`
+}
+
+type CreateOrderResponse struct {
+	ID              string                      `json:"id"`
+	InvoiceNumber   string                      `json:"invoice_number"`
+	Status          string                      `json:"status"`
+	Amount          int64                       `json:"amount"`
+	Breakdown       *PriceBreakdownResponse     `json:"breakdown"`
+	VoucherCode     string                      `json:"voucher_code,omitempty"`
+	PointsRedeemed  int64                       `json:"points_redeemed,omitempty"`
+	FulfillmentType string                      `json:"fulfillment_type"`
+	PickupAt        string                      `json:"pickup_at,omitempty"`
+	ScheduledFor    string                      `json:"scheduled_for,omitempty"`
+	ExpiredAt       string                      `json:"expired_at,omitempty"`
+	Payment         *PaymentInstructionResponse `json:"payment"`
+}
+
+type OrderItemResponse struct {
+	ID             string `json:"id"`
+	ProductID      string `json:"product_id"`
+	ProductName    string `json:"product_name"`
+	Qty            int    `json:"qty"`
+	Price          int64  `json:"price"`
+	Subtotal       int64  `json:"subtotal"`
+	RefundedQty    int    `json:"refunded_qty"`
+	RefundedAmount int64  `json:"refunded_amount"`
+}
+
+type PaymentLogResponse struct {
+	ID            string          `json:"id"`
+	TransactionID string          `json:"transaction_id"`
+	Status        string          `json:"status"`
+	RawResponse   json.RawMessage `json:"raw_response,omitempty"`
+	CreatedAt     string          `json:"created_at,omitempty"`
+}
+
+type OrderStatusHistoryResponse struct {
+	FromStatus string `json:"from_status"`
+	ToStatus   string `json:"to_status"`
+	Reason     string `json:"reason"`
+	Actor      string `json:"actor"`
+	CreatedAt  string `json:"created_at,omitempty"`
+}
+
+type RefundResponse struct {
+	ID          string               `json:"id"`
+	RefundKey   string               `json:"refund_key"`
+	Amount      int64                `json:"amount"`
+	Status      string               `json:"status"`
+	Destination string               `json:"destination"`
+	Reason      string               `json:"reason"`
+	Actor       string               `json:"actor"`
+	Items       []RefundItemResponse `json:"items,omitempty"`
+	CreatedAt   string               `json:"created_at,omitempty"`
+}
+
+type RefundItemResponse struct {
+	OrderItemID string `json:"order_item_id"`
+	Qty         int    `json:"qty"`
+	Amount      int64  `json:"amount"`
+}
+
+type SearchOrderRequest struct {
+	Status          string `json:"status" validate:"omitempty,max=20"`
+	PaymentType     string `json:"payment_type" validate:"omitempty,max=50"`
+	CustomerID      string `json:"customer_id" validate:"omitempty,uuid"`
+	FulfillmentType string `json:"fulfillment_type" validate:"omitempty,oneof=delivery pickup dine_in"`
+	DateFrom        string `json:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo          string `json:"date_to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// TrackOrderRequest order milik customer yang dipantau lewat stream, LastEventID dari header Last-Event-ID saat reconnect
+type TrackOrderRequest struct {
+	OrderIDs    []string `json:"order_ids" validate:"required,min=1,max=20,dive,uuid"`
+	LastEventID string   `json:"last_event_id" validate:"omitempty,numeric"`
+}
+
+// OrderStatusEventResponse data event "status" di stream order customer, juga isi event "snapshot" (tanpa from_status)
+type OrderStatusEventResponse struct {
+	OrderID         string `json:"order_id"`
+	InvoiceNumber   string `json:"invoice_number"`
+	FromStatus      string `json:"from_status,omitempty"`
+	Status          string `json:"status"`
+	FulfillmentType string `json:"fulfillment_type"`
+	PickupAt        string `json:"pickup_at,omitempty"`
+	ExpiredAt       string `json:"expired_at,omitempty"`
+	OccurredAt      string `json:"occurred_at"`
+}
+
+// SearchScheduledOrderRequest rentang tanggal waktu ambil pre-order (timezone toko), kosong = 7 hari mulai hari ini
+type SearchScheduledOrderRequest struct {
+	DateFrom string `json:"date_from" validate:"omitempty,datetime=2006-01-02"`
+	DateTo   string `json:"date_to" validate:"omitempty,datetime=2006-01-02"`
+}
+
+// ScheduledOrderDayResponse pre-order dalam satu hari, urut dari waktu ambil paling awal
+type ScheduledOrderDayResponse struct {
+	Date   string          `json:"date"`
+	Total  int             `json:"total"`
+	Orders []OrderResponse `json:"orders"`
+}
+
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" validate:"required,oneof=paid preparing ready completed failed expired"` // cancel & refund lewat endpoint sendiri
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason" validate:"required,max=255"`
+}
+
+// RefundOrderRequest items kosong berarti refund semua sisa item
+// destination kosong = original, order yang dibayar pakai wallet selalu kembali ke wallet
+type RefundOrderRequest struct {
+	Reason      string              `json:"reason" validate:"required,max=255"`
+	Destination string              `json:"destination,omitempty" validate:"omitempty,oneof=original wallet"`
+	Items       []RefundItemRequest `json:"items" validate:"omitempty,dive"`
+}
+
+type RefundItemRequest struct {
+	OrderItemID string `json:"order_item_id" validate:"required,uuid"`
+	Qty         int    `json:"qty" validate:"required,gt=0"`
+}
+
 type CreateOrderRequest struct {
 	CustomerID    string `json:"customer_id" validate:"required,uuid"`
-	CustomerName  string `json:"customer_name" validate:"required"`
-	CustomerEmail string `json:"customer_email" validate:"required,email"`
-	CustomerPhone string `json:"customer_phone" validate:"required"`
+	CustomerName  string `json:"customer_name" validate:"required,max=100"`
+	CustomerEmail string `json:"customer_email" validate:"required,email,max=100"`
+	CustomerPhone string `json:"customer_phone" validate:"required,max=20"`
 
 	Items []OrderItemRequest `json:"items" validate:"required,dive"`
 	Notes string             `json:"notes,omitempty"`
 
-	PaymentMethod   string `json:"payment_method" validate:"required"`
-	ShippingAddress string `json:"shipping_address,omitempty"`
+	PaymentMethod   string `json:"payment_method,omitempty"`                                     // wajib untuk core, opsional untuk snap, "wallet" = bayar pakai saldo (harus login)
+	CheckoutMode    string `json:"checkout_mode,omitempty" validate:"omitempty,oneof=core snap"` // kosong = PAYMENT_CHECKOUT_MODE
+	VoucherCode     string `json:"voucher_code,omitempty" validate:"omitempty,max=50"`
+	RedeemPoints    int64  `json:"redeem_points,omitempty" validate:"omitempty,gt=0"`                                         // poin loyalty yang dipakai sebagai diskon (harus login)
+	FulfillmentType string `json:"fulfillment_type,omitempty" validate:"omitempty,oneof=delivery pickup dine_in"`             // kosong = delivery
+	PickupSlotID    string `json:"pickup_slot_id,omitempty" validate:"required_with=PickupDate,omitempty,uuid"`               // pickup: slot + tanggal, atau scheduled_for
+	PickupDate      string `json:"pickup_date,omitempty" validate:"required_with=PickupSlotID,omitempty,datetime=2006-01-02"` // hari ini / besok
+	ScheduledFor    string `json:"scheduled_for,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`           // pre-order pickup (RFC3339), dibayar sekarang
+	ShippingAddress string `json:"shipping_address,omitempty"`                                                                // wajib untuk delivery, ongkir dihitung dari shipping zone
+	ShippingCity    string `json:"shipping_city,omitempty" validate:"omitempty,max=100"`                                      // kosong = kota di profil customer
+	ShippingPostal  string `json:"shipping_postal_code,omitempty" validate:"omitempty,numeric,max=10"`                        // kosong = kode pos di profil customer
 }
 
 type OrderItemRequest struct {
 	ProductID string `json:"product_id" validate:"required,uuid"`
-	Name      string `json:"name" validate:"required"`
-	Price     int64  `json:"price" validate:"required,gt=0"`
+	Name      string `json:"name,omitempty"`
+	Price     int64  `json:"price,omitempty" validate:"omitempty,gt=0"` // harga yang dilihat client, hanya untuk deteksi perubahan harga
 	Quantity  int    `json:"quantity" validate:"required,gt=0"`
 }
+
+type OutOfStockItem struct {
+	ProductID string `json:"product_id"`
+	Name      string `json:"name"`
+	Requested int    `json:"requested"`
+	Available int    `json:"available"`
+}
+
+type PriceChangedItem struct {
+	ProductID    string `json:"product_id"`
+	Name         string `json:"name"`
+	Price        int64  `json:"price"`         // harga dari client
+	CurrentPrice int64  `json:"current_price"` // harga terbaru di database
+}
+
+// OrderDocumentResponse file PDF invoice / receipt
+type OrderDocumentResponse struct {
+	FileName string
+	Content  []byte
+}
@@ -0,0 +1,18 @@
+package model
+
+// MidtransNotificationRequest payload HTTP notification dari Midtrans
+type MidtransNotificationRequest struct {
+	TransactionTime   string `json:"transaction_time"`
+	TransactionStatus string `json:"transaction_status" validate:"required"`
+	TransactionID     string `json:"transaction_id" validate:"required"`
+	StatusMessage     string `json:"status_message"`
+	StatusCode        string `json:"status_code" validate:"required"`
+	SignatureKey      string `json:"signature_key" validate:"required"`
+	PaymentType       string `json:"payment_type"`
+	OrderID           string `json:"order_id" validate:"required"`
+	MerchantID        string `json:"merchant_id"`
+	GrossAmount       string `json:"gross_amount" validate:"required"`
+	FraudStatus       string `json:"fraud_status"`
+	Currency          string `json:"currency"`
+	SettlementTime    string `json:"settlement_time"`
+}
@@ -0,0 +1,46 @@
+package model
+
+type PickupSlotResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Capacity  int    `json:"capacity"`
+	IsActive  bool   `json:"is_active"`
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// CreatePickupSlotRequest jam dalam format HH:MM (jam toko)
+type CreatePickupSlotRequest struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	StartTime string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"required,datetime=15:04"`
+	Capacity  int    `json:"capacity" validate:"required,gt=0"`
+	IsActive  *bool  `json:"is_active"`
+}
+
+type UpdatePickupSlotRequest struct {
+	Name      string `json:"name" validate:"required,max=100"`
+	StartTime string `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime   string `json:"end_time" validate:"required,datetime=15:04"`
+	Capacity  int    `json:"capacity" validate:"required,gt=0"`
+	IsActive  *bool  `json:"is_active"`
+}
+
+// PickupDayResponse slot yang bisa dipilih di satu tanggal, slot yang penuh tetap ditampilkan dengan available 0
+type PickupDayResponse struct {
+	Date  string                        `json:"date"`
+	Slots []PickupSlotAvailableResponse `json:"slots"`
+}
+
+type PickupSlotAvailableResponse struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	PickupAt  string `json:"pickup_at"`
+	Capacity  int    `json:"capacity"`
+	Booked    int64  `json:"booked"`
+	Available int64  `json:"available"`
+}
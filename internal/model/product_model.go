@@ -10,9 +10,11 @@ type ProductResponse struct {
 	Variant     string    `json:"variant"`
 	Price       int       `json:"price"`
 	Stock       int       `json:"stock"`
+	Reserved    int       `json:"reserved"`
 	Description string    `json:"description"`
 	ImageURL    string    `json:"image_url"`
 	CategoryID  uuid.UUID `json:"category_id"`
+	IsActive    bool      `json:"is_active"`
 	CreatedAt   string    `json:"created_at,omitempty"`
 	UpdatedAt   string    `json:"updated_at,omitempty"`
 }
@@ -39,6 +41,7 @@ type UpdateProductRequest struct {
 	Description string    `json:"description" validate:"required"`
 	ImageURL    string    `json:"image_url"`
 	CategoryID  uuid.UUID `json:"category_id" validate:"required"`
+	IsActive    *bool     `json:"is_active"`
 }
 
 type UpdateSpecialProductRequest struct {
@@ -0,0 +1,39 @@
+package model
+
+type RunReconciliationRequest struct {
+	DateFrom string `json:"date_from" validate:"required,datetime=2006-01-02"`
+	DateTo   string `json:"date_to" validate:"required,datetime=2006-01-02"`
+	AutoFix  bool   `json:"auto_fix"` // hanya perbaikan yang aman, sisanya tetap di laporan
+}
+
+type ReconciliationRunResponse struct {
+	ID            string                       `json:"id"`
+	DateFrom      string                       `json:"date_from"`
+	DateTo        string                       `json:"date_to"`
+	Trigger       string                       `json:"trigger"`
+	AutoFix       bool                         `json:"auto_fix"`
+	Status        string                       `json:"status"`
+	Error         string                       `json:"error,omitempty"`
+	TotalChecked  int                          `json:"total_checked"`
+	TotalMismatch int                          `json:"total_mismatch"`
+	TotalFixed    int                          `json:"total_fixed"`
+	TotalError    int                          `json:"total_error"`
+	FinishedAt    string                       `json:"finished_at,omitempty"`
+	Items         []ReconciliationItemResponse `json:"items,omitempty"`
+	CreatedAt     string                       `json:"created_at,omitempty"`
+}
+
+type ReconciliationItemResponse struct {
+	ID             string `json:"id"`
+	OrderID        string `json:"order_id,omitempty"`
+	TopUpID        string `json:"top_up_id,omitempty"`
+	InvoiceNumber  string `json:"invoice_number"`
+	Type           string `json:"type"`
+	OrderStatus    string `json:"order_status"`
+	ProviderStatus string `json:"provider_status"`
+	OrderAmount    int64  `json:"order_amount"`
+	ProviderAmount int64  `json:"provider_amount"`
+	LastLogStatus  string `json:"last_log_status"`
+	Fixed          bool   `json:"fixed"`
+	Note           string `json:"note"`
+}
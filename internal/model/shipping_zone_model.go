@@ -0,0 +1,35 @@
+package model
+
+type ShippingZoneResponse struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	City             string `json:"city"`
+	PostalCodePrefix string `json:"postal_code_prefix"`
+	Fee              int64  `json:"fee"`
+	MinOrder         int64  `json:"min_order"`
+	FreeThreshold    int64  `json:"free_threshold"`
+	IsActive         bool   `json:"is_active"`
+	CreatedAt        string `json:"created_at,omitempty"`
+	UpdatedAt        string `json:"updated_at,omitempty"`
+}
+
+// CreateShippingZoneRequest minimal salah satu city / postal_code_prefix harus diisi
+type CreateShippingZoneRequest struct {
+	Name             string `json:"name" validate:"required,max=100"`
+	City             string `json:"city" validate:"required_without=PostalCodePrefix,max=100"`
+	PostalCodePrefix string `json:"postal_code_prefix" validate:"omitempty,numeric,max=10"`
+	Fee              int64  `json:"fee" validate:"gte=0"`
+	MinOrder         int64  `json:"min_order" validate:"gte=0"`
+	FreeThreshold    int64  `json:"free_threshold" validate:"gte=0"`
+	IsActive         *bool  `json:"is_active"`
+}
+
+type UpdateShippingZoneRequest struct {
+	Name             string `json:"name" validate:"required,max=100"`
+	City             string `json:"city" validate:"required_without=PostalCodePrefix,max=100"`
+	PostalCodePrefix string `json:"postal_code_prefix" validate:"omitempty,numeric,max=10"`
+	Fee              int64  `json:"fee" validate:"gte=0"`
+	MinOrder         int64  `json:"min_order" validate:"gte=0"`
+	FreeThreshold    int64  `json:"free_threshold" validate:"gte=0"`
+	IsActive         *bool  `json:"is_active"`
+}
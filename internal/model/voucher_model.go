@@ -0,0 +1,57 @@
+package model
+
+type VoucherResponse struct {
+	ID               string   `json:"id"`
+	Code             string   `json:"code"`
+	Name             string   `json:"name"`
+	Description      string   `json:"description"`
+	Type             string   `json:"type"`
+	Value            int64    `json:"value"`
+	MinSpend         int64    `json:"min_spend"`
+	MaxDiscount      int64    `json:"max_discount"`
+	UsageLimit       int      `json:"usage_limit"`
+	PerCustomerLimit int      `json:"per_customer_limit"`
+	UsedCount        int      `json:"used_count"`
+	StartsAt         string   `json:"starts_at,omitempty"`
+	EndsAt           string   `json:"ends_at,omitempty"`
+	IsActive         bool     `json:"is_active"`
+	ProductIDs       []string `json:"product_ids,omitempty"`
+	CategoryIDs      []string `json:"category_ids,omitempty"`
+	CreatedAt        string   `json:"created_at,omitempty"`
+	UpdatedAt        string   `json:"updated_at,omitempty"`
+}
+
+// CreateVoucherRequest limit 0 berarti tanpa batas, product_ids & category_ids kosong berarti berlaku untuk semua produk
+type CreateVoucherRequest struct {
+	Code             string   `json:"code" validate:"required,alphanum,max=50"`
+	Name             string   `json:"name" validate:"required,max=100"`
+	Description      string   `json:"description"`
+	Type             string   `json:"type" validate:"required,oneof=percentage fixed"`
+	Value            int64    `json:"value" validate:"required,gt=0"`
+	MinSpend         int64    `json:"min_spend" validate:"gte=0"`
+	MaxDiscount      int64    `json:"max_discount" validate:"gte=0"`
+	UsageLimit       int      `json:"usage_limit" validate:"gte=0"`
+	PerCustomerLimit int      `json:"per_customer_limit" validate:"gte=0"`
+	StartsAt         string   `json:"starts_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt           string   `json:"ends_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	IsActive         *bool    `json:"is_active"`
+	ProductIDs       []string `json:"product_ids" validate:"omitempty,dive,uuid"`
+	CategoryIDs      []string `json:"category_ids" validate:"omitempty,dive,uuid"`
+}
+
+type UpdateVoucherRequest struct {
+	Code             string   `json:"code" validate:"required,alphanum,max=50"`
+	Name             string   `json:"name" validate:"required,max=100"`
+	Description      string   `json:"description"`
+	Type             string   `json:"type" validate:"required,oneof=percentage fixed"`
+	Value            int64    `json:"value" validate:"required,gt=0"`
+	MinSpend         int64    `json:"min_spend" validate:"gte=0"`
+	MaxDiscount      int64    `json:"max_discount" validate:"gte=0"`
+	UsageLimit       int      `json:"usage_limit" validate:"gte=0"`
+	PerCustomerLimit int      `json:"per_customer_limit" validate:"gte=0"`
+	StartsAt         string   `json:"starts_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	EndsAt           string   `json:"ends_at" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	IsActive         *bool    `json:"is_active"`
+	ProductIDs       []string `json:"product_ids" validate:"omitempty,dive,uuid"`
+	CategoryIDs      []string `json:"category_ids" validate:"omitempty,dive,uuid"`
+}
@@ -0,0 +1,37 @@
+package model
+
+type WalletResponse struct {
+	CustomerID string `json:"customer_id"`
+	Balance    int64  `json:"balance"`
+	UpdatedAt  string `json:"updated_at,omitempty"`
+}
+
+type WalletEntryResponse struct {
+	ID             string `json:"id"`
+	Type           string `json:"type"`
+	Amount         int64  `json:"amount"`
+	BalanceAfter   int64  `json:"balance_after"`
+	Source         string `json:"source"`
+	ReferenceID    string `json:"reference_id"`
+	CounterAccount string `json:"counter_account"`
+	Description    string `json:"description"`
+	Actor          string `json:"actor,omitempty"`
+	CreatedAt      string `json:"created_at,omitempty"`
+}
+
+// TopUpWalletRequest batas minimal & maksimal nominal dari WALLET_TOPUP_MIN / WALLET_TOPUP_MAX
+type TopUpWalletRequest struct {
+	Amount        int64  `json:"amount" validate:"required,gt=0"`
+	PaymentMethod string `json:"payment_method,omitempty"`                                     // wajib untuk core, opsional untuk snap
+	CheckoutMode  string `json:"checkout_mode,omitempty" validate:"omitempty,oneof=core snap"` // kosong = PAYMENT_CHECKOUT_MODE
+}
+
+type WalletTopUpResponse struct {
+	ID            string                      `json:"id"`
+	InvoiceNumber string                      `json:"invoice_number"`
+	Amount        int64                       `json:"amount"`
+	Status        string                      `json:"status"`
+	ExpiredAt     string                      `json:"expired_at,omitempty"`
+	Payment       *PaymentInstructionResponse `json:"payment"`
+	CreatedAt     string                      `json:"created_at,omitempty"`
+}
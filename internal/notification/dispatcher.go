@@ -0,0 +1,118 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultQueueSize = 256
+	sendTimeout      = 30 * time.Second
+	sendAttempts     = 3
+	drainTimeout     = 10 * time.Second
+)
+
+// Dispatcher kirim email di background supaya mail server yang lambat tidak menahan request.
+// Didaftarkan ke worker runner, email yang masih antri dicoba dikirim dulu saat shutdown.
+type Dispatcher struct {
+	Mailer    Mailer
+	Templates *Templates
+	From      string
+	StoreName string
+	Location  *time.Location // timezone toko untuk waktu di email
+	Log       *logrus.Logger
+	queue     chan *Email
+}
+
+func NewDispatcher(mailer Mailer, templates *Templates, from, storeName string, location *time.Location,
+	log *logrus.Logger, queueSize int) *Dispatcher {
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	return &Dispatcher{
+		Mailer:    mailer,
+		Templates: templates,
+		From:      from,
+		StoreName: storeName,
+		Location:  location,
+		Log:       log,
+		queue:     make(chan *Email, queueSize),
+	}
+}
+
+func (d *Dispatcher) Name() string {
+	return "email-dispatcher"
+}
+
+// Send masukkan email ke antrian tanpa menunggu, kalau antrian penuh email dibuang (cukup dicatat di log)
+func (d *Dispatcher) Send(email *Email) {
+	if email == nil || email.To == "" {
+		return
+	}
+
+	select {
+	case d.queue <- email:
+	default:
+		d.Log.Warnf("Email queue full, drop %s email to %s", email.Template, email.To)
+	}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			d.drain()
+			return
+		case email := <-d.queue:
+			d.deliver(ctx, email)
+		}
+	}
+}
+
+func (d *Dispatcher) drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+
+	for {
+		select {
+		case email := <-d.queue:
+			d.deliver(ctx, email)
+		default:
+			return
+		}
+		if ctx.Err() != nil {
+			d.Log.Warnf("Email drain timeout, %d email(s) not sent", len(d.queue))
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, email *Email) {
+	message, err := d.Templates.Render(email.Template, email.Data)
+	if err != nil {
+		d.Log.Warnf("Failed render %s email : %+v", email.Template, err)
+		return
+	}
+	message.From = d.From
+	message.To = email.To
+
+	for attempt := 1; attempt <= sendAttempts; attempt++ {
+		sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+		err = d.Mailer.Send(sendCtx, message)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		d.Log.Warnf("Failed send %s email to %s (attempt %d) : %+v", email.Template, email.To, attempt, err)
+		if attempt < sendAttempts {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Duration(attempt) * time.Second):
+			}
+		}
+	}
+}
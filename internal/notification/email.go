@@ -0,0 +1,88 @@
+package notification
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+)
+
+// Email satu email yang antri di Dispatcher, dirender saat dikirim
+type Email struct {
+	To       string
+	Template string
+	Data     any
+}
+
+type WelcomeData struct {
+	StoreName string
+	Name      string
+}
+
+type OrderData struct {
+	StoreName     string
+	CustomerName  string
+	InvoiceNumber string
+	Status        string
+	Items         []OrderItemData
+	Subtotal      int64
+	Discount      int64
+	ServiceCharge int64
+	Tax           int64
+	TaxInclusive  bool
+	DeliveryFee   int64
+	Total         int64
+	PaymentMethod string
+	ExpiredAt     string
+	RefundAmount  int64
+	RefundReason  string
+}
+
+type OrderItemData struct {
+	Name     string
+	Qty      int
+	Subtotal int64
+}
+
+// SendWelcome email setelah customer register
+func (d *Dispatcher) SendWelcome(customer *entity.Customer) {
+	d.Send(&Email{
+		To:       customer.Email,
+		Template: TemplateWelcome,
+		Data:     &WelcomeData{StoreName: d.StoreName, Name: customer.Name},
+	})
+}
+
+// SendOrder email terkait order (placed, payment received, ready, refund), refund hanya diisi untuk template refund
+func (d *Dispatcher) SendOrder(template, to, customerName string, order *entity.Order, refund *entity.Refund) {
+	data := &OrderData{
+		StoreName:     d.StoreName,
+		CustomerName:  customerName,
+		InvoiceNumber: order.InvoiceNumber,
+		Status:        order.Status,
+		Subtotal:      order.Subtotal,
+		Discount:      order.DiscountAmount,
+		ServiceCharge: order.ServiceCharge,
+		Tax:           order.TaxAmount,
+		TaxInclusive:  order.TaxInclusive,
+		DeliveryFee:   order.DeliveryFee,
+		Total:         order.Amount,
+		PaymentMethod: order.PaymentMethod,
+	}
+	if order.PaymentType != "" {
+		data.PaymentMethod = order.PaymentType
+	}
+	if order.ExpiredAt != nil {
+		data.ExpiredAt = order.ExpiredAt.In(d.Location).Format("02 Jan 2006 15:04 MST")
+	}
+	for _, item := range order.OrderItems {
+		data.Items = append(data.Items, OrderItemData{Name: item.ProductName, Qty: item.Qty, Subtotal: item.Subtotal})
+	}
+	if refund != nil {
+		data.RefundAmount = refund.Amount
+		data.RefundReason = refund.Reason
+	}
+
+	d.Send(&Email{
+		To:       to,
+		Template: template,
+		Data:     data,
+	})
+}
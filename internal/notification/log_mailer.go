@@ -0,0 +1,47 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._@-]+`)
+
+// LogMailer tidak benar-benar mengirim email, cukup dicatat di log. Kalau Dir diisi,
+// email lengkap (.eml) juga disimpan ke folder itu supaya bisa dibuka di mail client.
+type LogMailer struct {
+	Log *logrus.Logger
+	Dir string
+}
+
+func NewLogMailer(log *logrus.Logger, dir string) *LogMailer {
+	return &LogMailer{
+		Log: log,
+		Dir: dir,
+	}
+}
+
+func (l *LogMailer) Send(ctx context.Context, message *Message) error {
+	l.Log.Infof("Email to=%s subject=%q", message.To, message.Subject)
+	if l.Dir == "" {
+		l.Log.Debugf("Email body:\n%s", message.Text)
+		return nil
+	}
+
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(l.Dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000"), unsafeFileChars.ReplaceAllString(message.To, "_"))
+	return os.WriteFile(filepath.Join(l.Dir, name), data, 0o644)
+}
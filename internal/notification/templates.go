@@ -0,0 +1,74 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+)
+
+const (
+	TemplateWelcome         = "welcome"
+	TemplateOrderPlaced     = "order_placed"
+	TemplatePaymentReceived = "payment_received"
+	TemplateOrderReady      = "order_ready"
+	TemplateRefund          = "refund"
+)
+
+var subjects = map[string]string{
+	TemplateWelcome:         "Welcome to {{.StoreName}}",
+	TemplateOrderPlaced:     "Order {{.InvoiceNumber}} received",
+	TemplatePaymentReceived: "Payment received for {{.InvoiceNumber}}",
+	TemplateOrderReady:      "Order {{.InvoiceNumber}} is ready",
+	TemplateRefund:          "Refund for {{.InvoiceNumber}}",
+}
+
+//go:embed templates/*.html templates/*.txt
+var templateFS embed.FS
+
+var funcs = map[string]any{
+	"rupiah": utils.FormatRupiah,
+}
+
+// Templates template html + text tiap jenis email, di-parse sekali saat startup
+type Templates struct {
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+	subject *texttemplate.Template
+}
+
+func NewTemplates() *Templates {
+	subject := texttemplate.New("subject")
+	for name, value := range subjects {
+		texttemplate.Must(subject.New(name).Parse(value))
+	}
+
+	return &Templates{
+		html:    htmltemplate.Must(htmltemplate.New("html").Funcs(funcs).ParseFS(templateFS, "templates/*.html")),
+		text:    texttemplate.Must(texttemplate.New("text").Funcs(funcs).ParseFS(templateFS, "templates/*.txt")),
+		subject: subject,
+	}
+}
+
+// Render isi subject, html & text dari template name (ex: order_placed)
+func (t *Templates) Render(name string, data any) (*Message, error) {
+	var subject, html, text bytes.Buffer
+	if err := t.subject.ExecuteTemplate(&subject, name, data); err != nil {
+		return nil, fmt.Errorf("render subject %s: %w", name, err)
+	}
+	if err := t.html.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return nil, fmt.Errorf("render html %s: %w", name, err)
+	}
+	if err := t.text.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return nil, fmt.Errorf("render text %s: %w", name, err)
+	}
+
+	return &Message{
+		Subject: subject.String(),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
@@ -0,0 +1,205 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	DefaultOrderChannel = "daily-coffee:order-events"
+	DefaultReplayTTL    = 5 * time.Minute
+
+	subscriberBuffer = 64
+	publishTimeout   = 3 * time.Second
+	replayLimit      = 100 // event terakhir per customer yang disimpan untuk replay
+)
+
+// OrderBroker fan-out event order ke semua koneksi realtime. Event dipublish ke Redis pub/sub, jadi semua
+// instance API menerima event yang sama lalu meneruskannya ke subscriber lokal masing-masing.
+// Event juga disimpan sebentar per customer (sorted set, score = ID) supaya client yang reconnect bisa replay.
+// Didaftarkan ke worker runner, saat shutdown semua subscriber ditutup supaya stream ikut selesai.
+type OrderBroker struct {
+	Redis       *redis.Client
+	Channel     string
+	ReplayTTL   time.Duration
+	Log         *logrus.Logger
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+func NewOrderBroker(redisClient *redis.Client, channel string, replayTTL time.Duration, log *logrus.Logger) *OrderBroker {
+	if channel == "" {
+		channel = DefaultOrderChannel
+	}
+	if replayTTL <= 0 {
+		replayTTL = DefaultReplayTTL
+	}
+	return &OrderBroker{
+		Redis:       redisClient,
+		Channel:     channel,
+		ReplayTTL:   replayTTL,
+		Log:         log,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription event untuk satu koneksi. Channel Events ditutup kalau subscriber terlalu lambat
+// (buffer penuh) atau server shutdown, client cukup reconnect dan ambil snapshot baru.
+type Subscription struct {
+	broker *OrderBroker
+	events chan *OrderEvent
+	once   sync.Once
+}
+
+func (s *Subscription) Events() <-chan *OrderEvent {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.broker.remove(s)
+}
+
+func (b *OrderBroker) Name() string {
+	return "order-event-broker"
+}
+
+// Publish beri ID, simpan untuk replay, lalu kirim event ke Redis pub/sub.
+// Gagal publish hanya dicatat di log karena perubahan order sudah di-commit.
+func (b *OrderBroker) Publish(ctx context.Context, event *OrderEvent) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), publishTimeout)
+	defer cancel()
+
+	id, err := b.Redis.Incr(ctx, b.Channel+":seq").Result()
+	if err != nil {
+		b.Log.Warnf("Failed generate order event id, order=%s : %+v", event.OrderID, err)
+		return
+	}
+	event.ID = id
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		b.Log.Warnf("Failed marshal order event, order=%s : %+v", event.OrderID, err)
+		return
+	}
+
+	key := b.replayKey(event.CustomerID)
+	_, err = b.Redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(id), Member: data})
+		pipe.ZRemRangeByRank(ctx, key, 0, -replayLimit-1)
+		pipe.Expire(ctx, key, b.ReplayTTL)
+		pipe.Publish(ctx, b.Channel, data)
+		return nil
+	})
+	if err != nil {
+		b.Log.Warnf("Failed publish order event, order=%s : %+v", event.OrderID, err)
+	}
+}
+
+// Replay event milik customer dengan ID lebih besar dari afterID yang masih tersimpan, urut dari yang lama
+func (b *OrderBroker) Replay(ctx context.Context, customerID string, afterID int64) ([]*OrderEvent, error) {
+	values, err := b.Redis.ZRangeByScore(ctx, b.replayKey(customerID), &redis.ZRangeBy{
+		Min: "(" + strconv.FormatInt(afterID, 10),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*OrderEvent, 0, len(values))
+	for _, value := range values {
+		event := &OrderEvent{}
+		if err := json.Unmarshal([]byte(value), event); err != nil {
+			b.Log.Warnf("Failed unmarshal replay order event : %+v", err)
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (b *OrderBroker) replayKey(customerID string) string {
+	return b.Channel + ":customer:" + customerID
+}
+
+func (b *OrderBroker) Subscribe() *Subscription {
+	subscription := &Subscription{
+		broker: b,
+		events: make(chan *OrderEvent, subscriberBuffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		subscription.once.Do(func() { close(subscription.events) })
+		return subscription
+	}
+	b.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// Run dengarkan channel Redis sampai context di-cancel, go-redis reconnect sendiri kalau koneksi putus
+func (b *OrderBroker) Run(ctx context.Context) {
+	pubsub := b.Redis.Subscribe(ctx, b.Channel)
+	defer pubsub.Close()
+	defer b.closeAll()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			event := &OrderEvent{}
+			if err := json.Unmarshal([]byte(message.Payload), event); err != nil {
+				b.Log.Warnf("Failed unmarshal order event : %+v", err)
+				continue
+			}
+			b.broadcast(event)
+		}
+	}
+}
+
+func (b *OrderBroker) broadcast(event *OrderEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscription := range b.subscribers {
+		select {
+		case subscription.events <- event:
+		default:
+			b.Log.Warnf("Order event subscriber too slow, drop subscriber")
+			delete(b.subscribers, subscription)
+			subscription.once.Do(func() { close(subscription.events) })
+		}
+	}
+}
+
+func (b *OrderBroker) remove(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	delete(b.subscribers, subscription)
+	subscription.once.Do(func() { close(subscription.events) })
+}
+
+func (b *OrderBroker) closeAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for subscription := range b.subscribers {
+		delete(b.subscribers, subscription)
+		subscription.once.Do(func() { close(subscription.events) })
+	}
+}
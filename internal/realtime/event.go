@@ -0,0 +1,19 @@
+package realtime
+
+import (
+	"time"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
+
+// OrderEvent perubahan status order yang dikirim ke layar realtime (barista, customer).
+// Order berisi data order setelah perubahan, lengkap dengan item. ID urut naik dari Redis, dipakai untuk replay.
+type OrderEvent struct {
+	ID         int64                `json:"id"`
+	OrderID    string               `json:"order_id"`
+	CustomerID string               `json:"customer_id"`
+	FromStatus string               `json:"from_status"`
+	Status     string               `json:"status"`
+	Order      *model.OrderResponse `json:"order"`
+	OccurredAt time.Time            `json:"occurred_at"`
+}
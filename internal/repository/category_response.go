@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"github.com/google/uuid"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -22,3 +23,11 @@ func (r *CategoryRepository) ExistsByName(db *gorm.DB, name string) (bool, error
 	err := db.Model(&entity.Category{}).Where("name = ?", name).Count(&count).Error
 	return count > 0, err
 }
+
+func (r *CategoryRepository) FindByIDs(db *gorm.DB, ids []uuid.UUID) ([]entity.Category, error) {
+	var categories []entity.Category
+	if err := db.Where("id IN ?", ids).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type InvoiceCounterRepository struct {
+	Repository[entity.InvoiceCounter]
+	Log *logrus.Logger
+}
+
+func NewInvoiceCounterRepository(log *logrus.Logger) *InvoiceCounterRepository {
+	return &InvoiceCounterRepository{
+		Log: log,
+	}
+}
+
+// Next naikkan counter harian secara atomic, aman dipanggil dari banyak instance sekaligus.
+// Counter yang belum ada untuk hari itu di-seed dari nomor urut terbesar yang sudah terpakai di table model
+// (ex: INV-<hari ini>-000N dari generator lama), jadi nomor invoice tidak bentrok.
+func (r *InvoiceCounterRepository) Next(db *gorm.DB, format *utils.InvoiceFormat, now time.Time, model any) (int64, error) {
+	prefix, day := format.Prefix, format.Day(now)
+
+	var value int64
+	result := db.Raw(`UPDATE invoice_counters SET value = value + 1, updated_at = NOW()
+		WHERE prefix = ? AND day = ? RETURNING value`, prefix, day).
+		Scan(&value)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return value, nil
+	}
+
+	// subquery seed hanya jalan sekali per hari, saat row counter belum ada
+	numberPrefix := format.NumberPrefix(now)
+	sequence := "SUBSTRING(invoice_number FROM " + strconv.Itoa(len(numberPrefix)+1) + ")"
+	last := db.Model(model).
+		Select("COALESCE(MAX(CAST("+sequence+" AS BIGINT)), 0)").
+		Where("invoice_number LIKE ? AND "+sequence+" ~ '^[0-9]+$'", numberPrefix+"%")
+
+	err := db.Raw(`INSERT INTO invoice_counters (prefix, day, value, updated_at) VALUES (?, ?, (?) + 1, NOW())
+		ON CONFLICT (prefix, day) DO UPDATE SET value = invoice_counters.value + 1, updated_at = NOW()
+		RETURNING value`, prefix, day, last).
+		Scan(&value).Error
+	return value, err
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoyaltyRepository struct {
+	Repository[entity.LoyaltyAccount]
+	Log *logrus.Logger
+}
+
+func NewLoyaltyRepository(log *logrus.Logger) *LoyaltyRepository {
+	return &LoyaltyRepository{
+		Log: log,
+	}
+}
+
+func (r *LoyaltyRepository) FindByCustomerID(db *gorm.DB, customerID uuid.UUID) (*entity.LoyaltyAccount, error) {
+	var account entity.LoyaltyAccount
+	if err := db.Where("customer_id = ?", customerID).Take(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// FindOrCreateForUpdate buat akun kalau belum ada, lalu lock row-nya sampai transaksi selesai
+func (r *LoyaltyRepository) FindOrCreateForUpdate(db *gorm.DB, customerID uuid.UUID) (*entity.LoyaltyAccount, error) {
+	err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "customer_id"}}, DoNothing: true}).
+		Create(&entity.LoyaltyAccount{CustomerID: customerID}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var account entity.LoyaltyAccount
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("customer_id = ?", customerID).
+		Take(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *LoyaltyRepository) FindByIDForUpdate(db *gorm.DB, id uuid.UUID) (*entity.LoyaltyAccount, error) {
+	var account entity.LoyaltyAccount
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Take(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *LoyaltyRepository) CreateTransaction(db *gorm.DB, transaction *entity.LoyaltyTransaction) error {
+	return db.Create(transaction).Error
+}
+
+func (r *LoyaltyRepository) UpdateTransaction(db *gorm.DB, transaction *entity.LoyaltyTransaction) error {
+	return db.Save(transaction).Error
+}
+
+// FindOpenLots lot yang masih punya sisa poin, yang paling cepat expire duluan
+func (r *LoyaltyRepository) FindOpenLots(db *gorm.DB, accountID uuid.UUID) ([]entity.LoyaltyTransaction, error) {
+	var lots []entity.LoyaltyTransaction
+	err := db.Where("account_id = ? AND remaining > 0", accountID).
+		Order("expires_at asc nulls last, created_at, id").
+		Find(&lots).Error
+	return lots, err
+}
+
+func (r *LoyaltyRepository) FindExpiredLots(db *gorm.DB, accountID uuid.UUID, now time.Time) ([]entity.LoyaltyTransaction, error) {
+	var lots []entity.LoyaltyTransaction
+	err := db.Where("account_id = ? AND remaining > 0 AND expires_at <= ?", accountID, now).
+		Order("expires_at, id").
+		Find(&lots).Error
+	return lots, err
+}
+
+// FindExpiredAccountIDs akun yang punya lot sudah lewat tanggal expire tapi masih ada sisa poin
+func (r *LoyaltyRepository) FindExpiredAccountIDs(db *gorm.DB, now time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := db.Model(&entity.LoyaltyTransaction{}).
+		Distinct("account_id").
+		Where("remaining > 0 AND expires_at <= ?", now).
+		Limit(limit).
+		Pluck("account_id", &ids).Error
+	return ids, err
+}
+
+func (r *LoyaltyRepository) FindTransactions(db *gorm.DB, accountID uuid.UUID, pagination *utils.PaginationRequest) ([]entity.LoyaltyTransaction, int64, error) {
+	var transactions []entity.LoyaltyTransaction
+	var total int64
+
+	query := db.Model(&entity.LoyaltyTransaction{}).Where("account_id = ?", accountID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	err := query.Order("created_at desc, id").
+		Offset(offset).
+		Limit(pagination.Limit).
+		Find(&transactions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return transactions, total, nil
+}
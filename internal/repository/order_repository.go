@@ -1,11 +1,16 @@
 package repository
 
 import (
-	"context"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository struct {
@@ -19,10 +24,256 @@ func NewOrderRepository(log *logrus.Logger) *OrderRepository {
 	}
 }
 
-func (r *OrderRepository) GetTodayOrderCount(ctx context.Context, tx *gorm.DB) (int, error) {
-	var count int64
-	err := tx.Model(&entity.Order{}).
-		Where("DATE(created_at) = CURRENT_DATE").
-		Count(&count).Error
-	return int(count), err
+func (r *OrderRepository) FindItemsByOrderID(db *gorm.DB, orderID uuid.UUID) ([]entity.OrderItem, error) {
+	var items []entity.OrderItem
+	if err := db.Where("order_id = ?", orderID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *OrderRepository) UpdateItem(db *gorm.DB, item *entity.OrderItem) error {
+	return db.Save(item).Error
+}
+
+func (r *OrderRepository) FindByInvoiceNumberForUpdate(db *gorm.DB, invoiceNumber string) (*entity.Order, error) {
+	var order entity.Order
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("invoice_number = ?", invoiceNumber).
+		Take(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *OrderRepository) FindByIDForUpdate(db *gorm.DB, id uuid.UUID) (*entity.Order, error) {
+	var order entity.Order
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Take(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *OrderRepository) FindExpiredPending(db *gorm.DB, now time.Time, limit int) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := db.Where("status = ? AND expired_at IS NOT NULL AND expired_at < ?", entity.OrderStatusPending, now).
+		Order("expired_at").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// FindCreatedBetween order yang dibuat di rentang [from, to) beserta refund-nya, dipakai rekonsiliasi
+func (r *OrderRepository) FindCreatedBetween(db *gorm.DB, from, to time.Time) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := db.Preload("Refunds").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at").
+		Find(&orders).Error
+	return orders, err
+}
+
+// FindKitchenQueue order yang sedang di antrian barista beserta itemnya, yang paling lama masuk duluan.
+// Pre-order baru ikut setelah dirilis (masuk prep window).
+func (r *OrderRepository) FindKitchenQueue(db *gorm.DB) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := db.Preload("OrderItems", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at, id")
+	}).
+		Where("status IN ? AND (scheduled_for IS NULL OR kitchen_released_at IS NOT NULL)", entity.KitchenStatuses).
+		Order("created_at, id").
+		Find(&orders).Error
+	return orders, err
+}
+
+// FindScheduledToRelease pre-order yang sudah dibayar dan waktu rilisnya ke antrian barista sudah lewat
+func (r *OrderRepository) FindScheduledToRelease(db *gorm.DB, releaseBefore time.Time, limit int) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := db.Where("status = ? AND scheduled_for IS NOT NULL AND kitchen_released_at IS NULL AND scheduled_for <= ?",
+		entity.OrderStatusPaid, releaseBefore).
+		Order("scheduled_for").
+		Limit(limit).
+		Find(&orders).Error
+	return orders, err
+}
+
+// FindScheduledBetween pre-order dengan waktu ambil di rentang [from, to), order yang batal / tidak dibayar tidak ikut
+func (r *OrderRepository) FindScheduledBetween(db *gorm.DB, from, to time.Time) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := db.Preload("OrderItems", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at, id")
+	}).
+		Where("scheduled_for >= ? AND scheduled_for < ? AND status NOT IN ?", from, to,
+			[]string{entity.OrderStatusFailed, entity.OrderStatusExpired, entity.OrderStatusCancelled}).
+		Order("scheduled_for, id").
+		Find(&orders).Error
+	return orders, err
+}
+
+// PickupBookingCount jumlah order per slot pickup per waktu ambil
+type PickupBookingCount struct {
+	PickupSlotID uuid.UUID
+	PickupAt     time.Time
+	Total        int64
+}
+
+// pickupReleasedStatuses order dengan status ini tidak lagi memakai kapasitas slot pickup
+var pickupReleasedStatuses = []string{
+	entity.OrderStatusFailed,
+	entity.OrderStatusExpired,
+	entity.OrderStatusCancelled,
+	entity.OrderStatusRefunded,
+}
+
+func (r *OrderRepository) CountPickupBookings(db *gorm.DB, slotID uuid.UUID, pickupAt time.Time) (int64, error) {
+	var total int64
+	err := db.Model(&entity.Order{}).
+		Where("pickup_slot_id = ? AND pickup_at = ? AND status NOT IN ?", slotID, pickupAt, pickupReleasedStatuses).
+		Count(&total).Error
+	return total, err
+}
+
+// CountPickupBookingsBetween jumlah booking semua slot dengan waktu ambil di rentang [from, to)
+func (r *OrderRepository) CountPickupBookingsBetween(db *gorm.DB, from, to time.Time) ([]PickupBookingCount, error) {
+	var counts []PickupBookingCount
+	err := db.Model(&entity.Order{}).
+		Select("pickup_slot_id, pickup_at, COUNT(*) AS total").
+		Where("pickup_slot_id IS NOT NULL AND pickup_at >= ? AND pickup_at < ? AND status NOT IN ?", from, to, pickupReleasedStatuses).
+		Group("pickup_slot_id, pickup_at").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// CountUpcomingPickupBookings booking slot yang waktu ambilnya belum lewat
+func (r *OrderRepository) CountUpcomingPickupBookings(db *gorm.DB, slotID uuid.UUID, now time.Time) (int64, error) {
+	var total int64
+	err := db.Model(&entity.Order{}).
+		Where("pickup_slot_id = ? AND pickup_at >= ? AND status NOT IN ?", slotID, now, pickupReleasedStatuses).
+		Count(&total).Error
+	return total, err
+}
+
+var orderSortableColumns = map[string]bool{
+	"created_at":     true,
+	"updated_at":     true,
+	"amount":         true,
+	"invoice_number": true,
+	"status":         true,
+	"pickup_at":      true,
+	"scheduled_for":  true,
+}
+
+// FindAllWithFilter date_from & date_to dibaca sebagai tanggal di timezone toko (location), bukan timezone session database
+func (r *OrderRepository) FindAllWithFilter(db *gorm.DB, orders *[]entity.Order, pagination *utils.PaginationRequest,
+	filter *model.SearchOrderRequest, location *time.Location) (int64, error) {
+	var total int64
+
+	query := db.Model(&entity.Order{})
+
+	if pagination.Search != "" {
+		query = query.Where("invoice_number ILIKE ?", "%"+pagination.Search+"%")
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.PaymentType != "" {
+		query = query.Where("payment_type = ?", filter.PaymentType)
+	}
+	if filter.CustomerID != "" {
+		query = query.Where("user_id = ?", filter.CustomerID)
+	}
+	if filter.FulfillmentType != "" {
+		query = query.Where("fulfillment_type = ?", filter.FulfillmentType)
+	}
+	// rentang [date_from 00:00, date_to+1 00:00), format tanggal sudah divalidasi di usecase
+	if filter.DateFrom != "" {
+		from, _ := time.ParseInLocation("2006-01-02", filter.DateFrom, location)
+		query = query.Where("created_at >= ?", from)
+	}
+	if filter.DateTo != "" {
+		to, _ := time.ParseInLocation("2006-01-02", filter.DateTo, location)
+		query = query.Where("created_at < ?", to.AddDate(0, 0, 1))
+	}
+
+	// order, hanya kolom yang diizinkan
+	if orderSortableColumns[pagination.OrderBy] {
+		order := pagination.OrderBy
+		if strings.EqualFold(pagination.SortBy, "asc") {
+			order += " asc"
+		} else {
+			order += " desc"
+		}
+		query = query.Order(order)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	if err := query.Offset(offset).Limit(pagination.Limit).Find(orders).Error; err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *OrderRepository) FindDetailByID(db *gorm.DB, id any) (*entity.Order, error) {
+	var order entity.Order
+	err := db.Preload("OrderItems").
+		Preload("PaymentLogs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Preload("Histories", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Preload("Refunds", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Preload("Refunds.Items").
+		Where("id = ?", id).
+		Take(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindForDocument data order untuk invoice / receipt, urutan item & history dibuat tetap supaya PDF deterministik
+func (r *OrderRepository) FindForDocument(db *gorm.DB, id any) (*entity.Order, error) {
+	var order entity.Order
+	err := db.Preload("OrderItems", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at, product_name, id")
+	}).
+		Preload("Histories", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at, id")
+		}).
+		Where("id = ?", id).
+		Take(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindByIDsAndUserID order milik customer dari daftar id, id yang bukan milik customer tidak ikut
+func (r *OrderRepository) FindByIDsAndUserID(db *gorm.DB, ids []uuid.UUID, userID any) ([]entity.Order, error) {
+	var orders []entity.Order
+	err := db.Where("id IN ? AND user_id = ?", ids, userID).
+		Order("created_at").
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *OrderRepository) FindDetailByIDAndUserID(db *gorm.DB, id any, userID any) (*entity.Order, error) {
+	var order entity.Order
+	err := db.Preload("OrderItems").
+		Where("id = ? AND user_id = ?", id, userID).
+		Take(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
 }
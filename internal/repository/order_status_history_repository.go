@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/sirupsen/logrus"
+)
+
+type OrderStatusHistoryRepository struct {
+	Repository[entity.OrderStatusHistory]
+	Log *logrus.Logger
+}
+
+func NewOrderStatusHistoryRepository(log *logrus.Logger) *OrderStatusHistoryRepository {
+	return &OrderStatusHistoryRepository{
+		Log: log,
+	}
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type PaymentLogRepository struct {
+	Repository[entity.PaymentLog]
+	Log *logrus.Logger
+}
+
+func NewPaymentLogRepository(log *logrus.Logger) *PaymentLogRepository {
+	return &PaymentLogRepository{
+		Log: log,
+	}
+}
+
+func (r *PaymentLogRepository) FindLatestByOrderID(db *gorm.DB, orderID uuid.UUID) (*entity.PaymentLog, error) {
+	var paymentLog entity.PaymentLog
+	if err := db.Where("order_id = ?", orderID).Order("created_at desc").Take(&paymentLog).Error; err != nil {
+		return nil, err
+	}
+	return &paymentLog, nil
+}
+
+func (r *PaymentLogRepository) ExistsByTransactionStatus(db *gorm.DB, orderID uuid.UUID, transactionID, status string) (bool, error) {
+	var count int64
+	err := db.Model(&entity.PaymentLog{}).
+		Where("order_id = ? AND midtrans_transaction = ? AND status = ?", orderID, transactionID, status).
+		Count(&count).Error
+	return count > 0, err
+}
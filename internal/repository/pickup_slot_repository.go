@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PickupSlotRepository struct {
+	Repository[entity.PickupSlot]
+	Log *logrus.Logger
+}
+
+func NewPickupSlotRepository(log *logrus.Logger) *PickupSlotRepository {
+	return &PickupSlotRepository{
+		Log: log,
+	}
+}
+
+func (r *PickupSlotRepository) FindActive(db *gorm.DB) ([]entity.PickupSlot, error) {
+	var slots []entity.PickupSlot
+	if err := db.Where("is_active = ?", true).Order("start_time, end_time").Find(&slots).Error; err != nil {
+		return nil, err
+	}
+	return slots, nil
+}
+
+// FindByIDForUpdate lock slot selama checkout, jadi hitung booking + buat order tidak balapan dengan checkout lain
+func (r *PickupSlotRepository) FindByIDForUpdate(db *gorm.DB, id uuid.UUID) (*entity.PickupSlot, error) {
+	var slot entity.PickupSlot
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Take(&slot).Error; err != nil {
+		return nil, err
+	}
+	return &slot, nil
+}
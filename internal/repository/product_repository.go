@@ -7,11 +7,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepository struct {
@@ -47,6 +49,65 @@ func (r *ProductRepository) FindSpecialProduct(db *gorm.DB) (*entity.Product, er
 	return &p, nil
 }
 
+func (r *ProductRepository) FindByIDs(db *gorm.DB, ids []uuid.UUID) ([]entity.Product, error) {
+	var products []entity.Product
+	if err := db.Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// FindByIDsForUpdate lock row product (urut by id biar tidak deadlock antar checkout)
+func (r *ProductRepository) FindByIDsForUpdate(db *gorm.DB, ids []uuid.UUID) ([]entity.Product, error) {
+	var products []entity.Product
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", ids).
+		Order("id").
+		Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// UpdateDetail simpan edit dari CMS tanpa menulis reserved (dikelola checkout),
+// stock hanya ikut ditulis kalau memang diedit
+func (r *ProductRepository) UpdateDetail(db *gorm.DB, product *entity.Product, withStock bool) error {
+	columns := []string{"reserved"}
+	if !withStock {
+		columns = append(columns, "stock")
+	}
+	return db.Omit(columns...).Save(product).Error
+}
+
+func (r *ProductRepository) UpdateSpecial(db *gorm.DB, id uuid.UUID, isSpecial bool) error {
+	return db.Model(&entity.Product{}).Where("id = ?", id).
+		Update("is_special", isSpecial).Error
+}
+
+func (r *ProductRepository) ReserveStock(db *gorm.DB, id uuid.UUID, qty int) error {
+	return db.Model(&entity.Product{}).Where("id = ?", id).
+		Update("reserved", gorm.Expr("reserved + ?", qty)).Error
+}
+
+func (r *ProductRepository) CommitStock(db *gorm.DB, id uuid.UUID, qty int) error {
+	return db.Model(&entity.Product{}).Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"stock":    gorm.Expr("stock - ?", qty),
+			"reserved": gorm.Expr("GREATEST(reserved - ?, 0)", qty),
+		}).Error
+}
+
+func (r *ProductRepository) ReleaseStock(db *gorm.DB, id uuid.UUID, qty int) error {
+	return db.Model(&entity.Product{}).Where("id = ?", id).
+		Update("reserved", gorm.Expr("GREATEST(reserved - ?, 0)", qty)).Error
+}
+
+// RestoreStock kembalikan stok yang sudah di-commit, dipakai saat refund
+func (r *ProductRepository) RestoreStock(db *gorm.DB, id uuid.UUID, qty int) error {
+	return db.Model(&entity.Product{}).Where("id = ?", id).
+		Update("stock", gorm.Expr("stock + ?", qty)).Error
+}
+
 func (r *Repository[T]) FindAllWithRedis(db *gorm.DB, entities *[]T, pagination *utils.PaginationRequest) (int64, error) {
 	var total int64
 	entityName := fmt.Sprintf("%T", new(T))
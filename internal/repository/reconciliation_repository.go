@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ReconciliationRepository struct {
+	Repository[entity.ReconciliationRun]
+	Log *logrus.Logger
+}
+
+func NewReconciliationRepository(log *logrus.Logger) *ReconciliationRepository {
+	return &ReconciliationRepository{
+		Log: log,
+	}
+}
+
+func (r *ReconciliationRepository) CreateItem(db *gorm.DB, item *entity.ReconciliationItem) error {
+	return db.Create(item).Error
+}
+
+func (r *ReconciliationRepository) FindDetailByID(db *gorm.DB, id any) (*entity.ReconciliationRun, error) {
+	var run entity.ReconciliationRun
+	err := db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).
+		Where("id = ?", id).
+		Take(&run).Error
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// CountQueued jumlah run manual yang masih menunggu diproses
+func (r *ReconciliationRepository) CountQueued(db *gorm.DB) (int64, error) {
+	var total int64
+	err := db.Model(&entity.ReconciliationRun{}).
+		Where("status = ?", entity.ReconciliationStatusQueued).
+		Count(&total).Error
+	return total, err
+}
+
+// ClaimNextQueued ambil run queued paling lama sekaligus tandai running dalam satu query.
+// SKIP LOCKED supaya dua instance tidak pernah memproses run yang sama.
+func (r *ReconciliationRepository) ClaimNextQueued(db *gorm.DB) (*entity.ReconciliationRun, error) {
+	var run entity.ReconciliationRun
+	result := db.Raw(`UPDATE reconciliation_runs SET status = ?
+		WHERE id = (SELECT id FROM reconciliation_runs WHERE status = ? ORDER BY created_at LIMIT 1 FOR UPDATE SKIP LOCKED)
+		RETURNING *`, entity.ReconciliationStatusRunning, entity.ReconciliationStatusQueued).
+		Scan(&run)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &run, nil
+}
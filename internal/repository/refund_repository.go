@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type RefundRepository struct {
+	Repository[entity.Refund]
+	Log *logrus.Logger
+}
+
+func NewRefundRepository(log *logrus.Logger) *RefundRepository {
+	return &RefundRepository{
+		Log: log,
+	}
+}
+
+func (r *RefundRepository) CountByOrderID(db *gorm.DB, orderID uuid.UUID) (int64, error) {
+	var total int64
+	err := db.Model(&entity.Refund{}).Where("order_id = ?", orderID).Count(&total).Error
+	return total, err
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ShippingZoneRepository struct {
+	Repository[entity.ShippingZone]
+	Log *logrus.Logger
+}
+
+func NewShippingZoneRepository(log *logrus.Logger) *ShippingZoneRepository {
+	return &ShippingZoneRepository{
+		Log: log,
+	}
+}
+
+func (r *ShippingZoneRepository) FindActive(db *gorm.DB) ([]entity.ShippingZone, error) {
+	var zones []entity.ShippingZone
+	if err := db.Where("is_active = ?", true).Order("created_at").Find(&zones).Error; err != nil {
+		return nil, err
+	}
+	return zones, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type VoucherRepository struct {
+	Repository[entity.Voucher]
+	Log *logrus.Logger
+}
+
+func NewVoucherRepository(log *logrus.Logger) *VoucherRepository {
+	return &VoucherRepository{
+		Log: log,
+	}
+}
+
+func (r *VoucherRepository) ExistsByCode(db *gorm.DB, code string, excludeID uuid.UUID) (bool, error) {
+	var count int64
+	err := db.Model(&entity.Voucher{}).Where("code = ? AND id <> ?", code, excludeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *VoucherRepository) FindDetailByID(db *gorm.DB, id any) (*entity.Voucher, error) {
+	var voucher entity.Voucher
+	err := db.Preload("Products").
+		Preload("Categories").
+		Where("id = ?", id).
+		Take(&voucher).Error
+	if err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+// FindByIDForUpdate lock row voucher saat diedit CMS, supaya used_count dari checkout yang jalan bersamaan tidak tertimpa
+func (r *VoucherRepository) FindByIDForUpdate(db *gorm.DB, id any) (*entity.Voucher, error) {
+	var voucher entity.Voucher
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Take(&voucher).Error
+	if err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+// FindByCodeForUpdate lock row voucher selama checkout, jadi kuota tidak bisa dipakai dua order sekaligus
+func (r *VoucherRepository) FindByCodeForUpdate(db *gorm.DB, code string) (*entity.Voucher, error) {
+	var voucher entity.Voucher
+	err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Products").
+		Preload("Categories").
+		Where("code = ?", code).
+		Take(&voucher).Error
+	if err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+// ReplaceRestrictions ganti daftar produk & kategori voucher
+func (r *VoucherRepository) ReplaceRestrictions(db *gorm.DB, voucher *entity.Voucher, products []entity.Product, categories []entity.Category) error {
+	if err := db.Model(voucher).Association("Products").Replace(products); err != nil {
+		return err
+	}
+	return db.Model(voucher).Association("Categories").Replace(categories)
+}
+
+func (r *VoucherRepository) AddUsage(db *gorm.DB, id uuid.UUID, delta int) error {
+	return db.Model(&entity.Voucher{}).Where("id = ?", id).
+		Update("used_count", gorm.Expr("GREATEST(used_count + ?, 0)", delta)).Error
+}
+
+func (r *VoucherRepository) CreateRedemption(db *gorm.DB, redemption *entity.VoucherRedemption) error {
+	return db.Create(redemption).Error
+}
+
+func (r *VoucherRepository) CountCustomerRedemptions(db *gorm.DB, voucherID, customerID uuid.UUID) (int64, error) {
+	var total int64
+	err := db.Model(&entity.VoucherRedemption{}).
+		Where("voucher_id = ? AND customer_id = ? AND status = ?", voucherID, customerID, entity.RedemptionStatusApplied).
+		Count(&total).Error
+	return total, err
+}
+
+func (r *VoucherRepository) CountRedemptions(db *gorm.DB, voucherID uuid.UUID) (int64, error) {
+	var total int64
+	err := db.Model(&entity.VoucherRedemption{}).Where("voucher_id = ?", voucherID).Count(&total).Error
+	return total, err
+}
+
+func (r *VoucherRepository) FindAppliedRedemption(db *gorm.DB, orderID uuid.UUID) (*entity.VoucherRedemption, error) {
+	var redemption entity.VoucherRedemption
+	err := db.Where("order_id = ? AND status = ?", orderID, entity.RedemptionStatusApplied).
+		Take(&redemption).Error
+	if err != nil {
+		return nil, err
+	}
+	return &redemption, nil
+}
+
+func (r *VoucherRepository) UpdateRedemption(db *gorm.DB, redemption *entity.VoucherRedemption) error {
+	return db.Save(redemption).Error
+}
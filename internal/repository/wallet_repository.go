@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WalletRepository struct {
+	Repository[entity.Wallet]
+	Log *logrus.Logger
+}
+
+func NewWalletRepository(log *logrus.Logger) *WalletRepository {
+	return &WalletRepository{
+		Log: log,
+	}
+}
+
+func (r *WalletRepository) FindByCustomerID(db *gorm.DB, customerID uuid.UUID) (*entity.Wallet, error) {
+	var wallet entity.Wallet
+	if err := db.Where("customer_id = ?", customerID).Take(&wallet).Error; err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+// FindOrCreateForUpdate buat wallet kalau belum ada, lalu lock row-nya sampai transaksi selesai
+func (r *WalletRepository) FindOrCreateForUpdate(db *gorm.DB, customerID uuid.UUID) (*entity.Wallet, error) {
+	err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "customer_id"}}, DoNothing: true}).
+		Create(&entity.Wallet{CustomerID: customerID}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var wallet entity.Wallet
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("customer_id = ?", customerID).
+		Take(&wallet).Error; err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (r *WalletRepository) CreateEntry(db *gorm.DB, entry *entity.WalletEntry) error {
+	return db.Create(entry).Error
+}
+
+func (r *WalletRepository) FindEntries(db *gorm.DB, walletID uuid.UUID, pagination *utils.PaginationRequest) ([]entity.WalletEntry, int64, error) {
+	var entries []entity.WalletEntry
+	var total int64
+
+	query := db.Model(&entity.WalletEntry{}).Where("wallet_id = ?", walletID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (pagination.Page - 1) * pagination.Limit
+	err := query.Order("created_at desc, id").
+		Offset(offset).
+		Limit(pagination.Limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (r *WalletRepository) CreateTopUp(db *gorm.DB, topUp *entity.WalletTopUp) error {
+	return db.Create(topUp).Error
+}
+
+func (r *WalletRepository) UpdateTopUp(db *gorm.DB, topUp *entity.WalletTopUp) error {
+	return db.Save(topUp).Error
+}
+
+func (r *WalletRepository) FindTopUpByInvoiceForUpdate(db *gorm.DB, invoiceNumber string) (*entity.WalletTopUp, error) {
+	var topUp entity.WalletTopUp
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("invoice_number = ?", invoiceNumber).
+		Take(&topUp).Error; err != nil {
+		return nil, err
+	}
+	return &topUp, nil
+}
+
+func (r *WalletRepository) FindExpiredPendingTopUps(db *gorm.DB, now time.Time, limit int) ([]entity.WalletTopUp, error) {
+	var topUps []entity.WalletTopUp
+	err := db.Where("status = ? AND expired_at IS NOT NULL AND expired_at < ?", entity.TopUpStatusPending, now).
+		Order("expired_at").
+		Limit(limit).
+		Find(&topUps).Error
+	return topUps, err
+}
+
+// FindTopUpsCreatedBetween top up yang dibuat di rentang [from, to), dipakai rekonsiliasi
+func (r *WalletRepository) FindTopUpsCreatedBetween(db *gorm.DB, from, to time.Time) ([]entity.WalletTopUp, error) {
+	var topUps []entity.WalletTopUp
+	err := db.Where("created_at >= ? AND created_at < ?", from, to).
+		Order("created_at").
+		Find(&topUps).Error
+	return topUps, err
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+)
+
+var fakeMethods = []string{"gopay", "qris", "bca", "bni", "bri", "permata"}
+
+const fakeExpiryDuration = 15 * time.Minute
+
+type fakeTransaction struct {
+	TransactionID string
+	OrderID       string
+	PaymentType   string
+	GrossAmount   int64
+	Refunded      int64
+	Status        string
+}
+
+// FakePaymentService payment provider in-memory untuk local development & test.
+// Response deterministik: transaction id & VA number diturunkan dari order id dan urutan charge.
+type FakePaymentService struct {
+	ServerKey string
+	Clock     func() time.Time
+
+	mu           sync.Mutex
+	sequence     int
+	transactions map[string]*fakeTransaction
+}
+
+func NewFakePaymentService(serverKey string) *FakePaymentService {
+	return &FakePaymentService{
+		ServerKey:    serverKey,
+		Clock:        time.Now,
+		transactions: make(map[string]*fakeTransaction),
+	}
+}
+
+func (f *FakePaymentService) Name() string {
+	return PaymentProviderFake
+}
+
+func (f *FakePaymentService) SupportedMethods() []string {
+	return fakeMethods
+}
+
+func (f *FakePaymentService) Charge(req *coreapi.ChargeReq) (*coreapi.ChargeResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	orderID := req.TransactionDetails.OrderID
+	if _, exists := f.transactions[orderID]; exists {
+		return nil, fmt.Errorf("%w: order_id %s has already been utilized", utils.ErrPayment, orderID)
+	}
+	if req.TransactionDetails.GrossAmt <= 0 {
+		return nil, fmt.Errorf("%w: gross_amount must be greater than 0", utils.ErrPayment)
+	}
+
+	f.sequence++
+	trx := &fakeTransaction{
+		TransactionID: "fake-" + orderID,
+		OrderID:       orderID,
+		PaymentType:   string(req.PaymentType),
+		GrossAmount:   req.TransactionDetails.GrossAmt,
+		Status:        "pending",
+	}
+	f.transactions[orderID] = trx
+
+	now := f.Clock()
+	resp := &coreapi.ChargeResponse{
+		TransactionID:     trx.TransactionID,
+		OrderID:           orderID,
+		GrossAmount:       formatFakeAmount(trx.GrossAmount),
+		PaymentType:       trx.PaymentType,
+		TransactionTime:   formatFakeTime(now),
+		TransactionStatus: trx.Status,
+		StatusCode:        "201",
+		StatusMessage:     "Success, fake transaction is created",
+		Currency:          "IDR",
+		ExpiryTime:        formatFakeTime(now.Add(fakeExpiryDuration)),
+	}
+
+	switch req.PaymentType {
+	case coreapi.PaymentTypeQris:
+		resp.QRString = "FAKE-QRIS-" + orderID
+		resp.Actions = []coreapi.Action{
+			{Name: "generate-qr-code", Method: "GET", URL: "https://fake-payment.local/qris/" + orderID},
+		}
+	case coreapi.PaymentTypeGopay:
+		resp.Actions = []coreapi.Action{
+			{Name: "generate-qr-code", Method: "GET", URL: "https://fake-payment.local/gopay/qr/" + orderID},
+			{Name: "deeplink-redirect", Method: "GET", URL: "https://fake-payment.local/gopay/deeplink/" + orderID},
+		}
+	case coreapi.PaymentTypeBankTransfer:
+		bank := ""
+		if req.BankTransfer != nil {
+			bank = string(req.BankTransfer.Bank)
+		}
+		vaNumber := fmt.Sprintf("8808%08d", f.sequence)
+		if bank == "permata" {
+			resp.PermataVaNumber = vaNumber
+		} else {
+			resp.VaNumbers = []coreapi.VANumber{{Bank: bank, VANumber: vaNumber}}
+		}
+	}
+
+	return resp, nil
+}
+
+// CreateSnap transaksi dibuat pending tanpa payment type, sama seperti Snap sebelum customer pilih metode
+func (f *FakePaymentService) CreateSnap(req *snap.Request) (*snap.Response, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	orderID := req.TransactionDetails.OrderID
+	if _, exists := f.transactions[orderID]; exists {
+		return nil, fmt.Errorf("%w: order_id %s has already been utilized", utils.ErrPayment, orderID)
+	}
+	if req.TransactionDetails.GrossAmt <= 0 {
+		return nil, fmt.Errorf("%w: gross_amount must be greater than 0", utils.ErrPayment)
+	}
+
+	f.sequence++
+	f.transactions[orderID] = &fakeTransaction{
+		TransactionID: "fake-" + orderID,
+		OrderID:       orderID,
+		GrossAmount:   req.TransactionDetails.GrossAmt,
+		Status:        "pending",
+	}
+
+	token := "fake-snap-" + orderID
+	return &snap.Response{
+		Token:       token,
+		RedirectURL: "https://fake-payment.local/snap/v2/vtweb/" + token,
+	}, nil
+}
+
+func (f *FakePaymentService) Status(orderID string) (*coreapi.TransactionStatusResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	trx, ok := f.transactions[orderID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", utils.ErrPaymentNotFound, orderID)
+	}
+
+	return &coreapi.TransactionStatusResponse{
+		TransactionID:     trx.TransactionID,
+		OrderID:           trx.OrderID,
+		GrossAmount:       formatFakeAmount(trx.GrossAmount),
+		PaymentType:       trx.PaymentType,
+		TransactionStatus: trx.Status,
+		StatusCode:        "200",
+		Currency:          "IDR",
+		RefundAmount:      formatFakeAmount(trx.Refunded),
+	}, nil
+}
+
+func (f *FakePaymentService) Cancel(orderID string) (*coreapi.CancelResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	trx, ok := f.transactions[orderID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", utils.ErrPaymentNotFound, orderID)
+	}
+	if trx.Status != "pending" && trx.Status != "capture" {
+		return nil, fmt.Errorf("%w: transaction with status %s cannot be cancelled", utils.ErrPayment, trx.Status)
+	}
+
+	trx.Status = "cancel"
+	return &coreapi.CancelResponse{
+		TransactionID:     trx.TransactionID,
+		OrderID:           trx.OrderID,
+		GrossAmount:       formatFakeAmount(trx.GrossAmount),
+		PaymentType:       trx.PaymentType,
+		TransactionStatus: trx.Status,
+		StatusCode:        "200",
+		StatusMessage:     "Success, transaction is canceled",
+		Currency:          "IDR",
+	}, nil
+}
+
+func (f *FakePaymentService) Refund(orderID string, req *coreapi.RefundReq) (*coreapi.RefundResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	trx, ok := f.transactions[orderID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", utils.ErrPaymentNotFound, orderID)
+	}
+	if trx.Status != "settlement" && trx.Status != "partial_refund" {
+		return nil, fmt.Errorf("%w: transaction with status %s cannot be refunded", utils.ErrPayment, trx.Status)
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = trx.GrossAmount - trx.Refunded
+	}
+	if amount <= 0 || trx.Refunded+amount > trx.GrossAmount {
+		return nil, fmt.Errorf("%w: refund amount exceeds remaining amount", utils.ErrPayment)
+	}
+
+	trx.Refunded += amount
+	trx.Status = "partial_refund"
+	if trx.Refunded == trx.GrossAmount {
+		trx.Status = "refund"
+	}
+
+	return &coreapi.RefundResponse{
+		TransactionID:     trx.TransactionID,
+		OrderID:           trx.OrderID,
+		GrossAmount:       formatFakeAmount(trx.GrossAmount),
+		PaymentType:       trx.PaymentType,
+		TransactionStatus: trx.Status,
+		StatusCode:        "200",
+		StatusMessage:     "Success, refund request is approved",
+		Currency:          "IDR",
+		RefundAmount:      formatFakeAmount(amount),
+		RefundKey:         req.RefundKey,
+	}, nil
+}
+
+func (f *FakePaymentService) VerifySignature(orderID, statusCode, grossAmount, signature string) bool {
+	return utils.VerifyMidtransSignature(orderID, statusCode, grossAmount, f.ServerKey, signature)
+}
+
+// SetStatus simulasi perubahan status dari sisi provider (ex: settlement, expire) untuk test
+func (f *FakePaymentService) SetStatus(orderID, transactionStatus string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	trx, ok := f.transactions[orderID]
+	if !ok {
+		return fmt.Errorf("%w: %s", utils.ErrPaymentNotFound, orderID)
+	}
+	trx.Status = transactionStatus
+	return nil
+}
+
+func formatFakeAmount(amount int64) string {
+	return strconv.FormatInt(amount, 10) + ".00"
+}
+
+func formatFakeTime(t time.Time) string {
+	return t.In(time.FixedZone("WIB", 7*60*60)).Format("2006-01-02 15:04:05")
+}
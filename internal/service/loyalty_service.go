@@ -0,0 +1,49 @@
+package service
+
+import "time"
+
+// LoyaltyProgram aturan poin loyalty: earn dari order paid, redeem jadi diskon di checkout, expire setelah periode tertentu.
+// Multiplier disimpan dalam basis point (20000 = 2x) supaya perhitungan tetap integer.
+type LoyaltyProgram struct {
+	EarnUnit    int64            // rupiah per 1 poin, 0 = program earn tidak aktif
+	Multipliers map[string]int64 // slug kategori → basis point, kategori yang tidak ada = 1x
+	PointValue  int64            // nilai 1 poin dalam rupiah saat redeem
+	MinRedeem   int64            // minimal poin sekali redeem
+	Expiry      time.Duration    // 0 = poin tidak pernah expire
+}
+
+type LoyaltyLine struct {
+	CategorySlug string
+	Amount       int64 // nominal item yang benar-benar dibayar (setelah diskon)
+}
+
+// Earn jumlah poin dari item order, dibulatkan ke bawah
+func (p *LoyaltyProgram) Earn(lines []LoyaltyLine) int64 {
+	if p.EarnUnit <= 0 {
+		return 0
+	}
+
+	var weighted int64
+	for _, line := range lines {
+		multiplier, ok := p.Multipliers[line.CategorySlug]
+		if !ok {
+			multiplier = 10000
+		}
+		weighted += line.Amount * multiplier
+	}
+	return max(weighted, 0) / (p.EarnUnit * 10000)
+}
+
+// Discount nilai rupiah dari poin yang di-redeem
+func (p *LoyaltyProgram) Discount(points int64) int64 {
+	return points * p.PointValue
+}
+
+// ExpiresAt tanggal expire lot poin yang didapat sekarang
+func (p *LoyaltyProgram) ExpiresAt(now time.Time) *time.Time {
+	if p.Expiry <= 0 {
+		return nil
+	}
+	expiresAt := now.Add(p.Expiry)
+	return &expiresAt
+}
@@ -1,15 +1,31 @@
 package service
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
 )
 
+var midtransMethods = []string{"gopay", "qris", "shopeepay", "bca", "bni", "bri", "permata"}
+
 type MidtransService struct {
-	client *coreapi.Client
+	client     *coreapi.Client
+	snapClient *snap.Client
+}
+
+func NewMidtransService(client *coreapi.Client, snapClient *snap.Client) *MidtransService {
+	return &MidtransService{client: client, snapClient: snapClient}
 }
 
-func NewMidtransService(client *coreapi.Client) *MidtransService {
-	return &MidtransService{client: client}
+func (m *MidtransService) Name() string {
+	return PaymentProviderMidtrans
+}
+
+func (m *MidtransService) SupportedMethods() []string {
+	return midtransMethods
 }
 
 func (m *MidtransService) Charge(req *coreapi.ChargeReq) (*coreapi.ChargeResponse, error) {
@@ -20,3 +36,45 @@ func (m *MidtransService) Charge(req *coreapi.ChargeReq) (*coreapi.ChargeRespons
 	}
 	return resp, nil
 }
+
+func (m *MidtransService) CreateSnap(req *snap.Request) (*snap.Response, error) {
+	resp, err := m.snapClient.CreateTransaction(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (m *MidtransService) Status(orderID string) (*coreapi.TransactionStatusResponse, error) {
+	resp, err := m.client.CheckTransaction(orderID)
+	if err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", utils.ErrPaymentNotFound, orderID)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (m *MidtransService) Cancel(orderID string) (*coreapi.CancelResponse, error) {
+	resp, err := m.client.CancelTransaction(orderID)
+	if err != nil {
+		if err.StatusCode == http.StatusNotFound {
+			return nil, fmt.Errorf("%w: %s", utils.ErrPaymentNotFound, orderID)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (m *MidtransService) Refund(orderID string, req *coreapi.RefundReq) (*coreapi.RefundResponse, error) {
+	resp, err := m.client.RefundTransaction(orderID, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (m *MidtransService) VerifySignature(orderID, statusCode, grossAmount, signature string) bool {
+	return utils.VerifyMidtransSignature(orderID, statusCode, grossAmount, m.client.ServerKey, signature)
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+)
+
+const (
+	PaymentProviderMidtrans = "midtrans"
+	PaymentProviderFake     = "fake"
+)
+
+// PaymentProvider abstraksi payment gateway yang dipakai order flow.
+// Request & response pakai tipe coreapi supaya mapping ke Midtrans tetap 1:1.
+type PaymentProvider interface {
+	Name() string
+	SupportedMethods() []string
+	Charge(req *coreapi.ChargeReq) (*coreapi.ChargeResponse, error)
+	CreateSnap(req *snap.Request) (*snap.Response, error)
+	Status(orderID string) (*coreapi.TransactionStatusResponse, error)
+	Cancel(orderID string) (*coreapi.CancelResponse, error)
+	Refund(orderID string, req *coreapi.RefundReq) (*coreapi.RefundResponse, error)
+	VerifySignature(orderID, statusCode, grossAmount, signature string) bool
+}
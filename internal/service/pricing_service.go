@@ -0,0 +1,156 @@
+package service
+
+import (
+	"github.com/midtrans/midtrans-go"
+)
+
+const (
+	RoundingNone    = "none"
+	RoundingUp      = "up"
+	RoundingDown    = "down"
+	RoundingNearest = "nearest"
+)
+
+// PricingEngine hitung rincian harga order: subtotal, diskon, service charge, pajak (PPN), ongkir dan pembulatan.
+// Rate disimpan dalam basis point (1100 = 11%) supaya perhitungan tetap integer.
+type PricingEngine struct {
+	TaxRate      int64  // basis point
+	ServiceRate  int64  // basis point
+	TaxInclusive bool   // harga menu sudah termasuk pajak
+	TaxOnService bool   // service charge ikut dikenakan pajak
+	Rounding     string // none, up, down, nearest
+	RoundingUnit int64  // ex: 100 → dibulatkan ke ratusan rupiah
+}
+
+type PricingLine struct {
+	ID    string
+	Name  string
+	Price int64 // harga per unit
+	Qty   int
+}
+
+type PricingResult struct {
+	Lines         []PricingLine
+	Subtotal      int64
+	Discount      int64
+	ServiceCharge int64
+	Tax           int64
+	DeliveryFee   int64
+	Rounding      int64 // bisa negatif kalau dibulatkan ke bawah
+	Total         int64
+	TaxInclusive  bool
+	TaxRate       int64
+	ServiceRate   int64
+}
+
+// Calculate ongkir tidak kena service charge maupun pajak, langsung ditambahkan sebelum pembulatan
+func (p *PricingEngine) Calculate(lines []PricingLine, discount, deliveryFee int64) *PricingResult {
+	result := &PricingResult{
+		Lines:        lines,
+		TaxInclusive: p.TaxInclusive,
+		TaxRate:      p.TaxRate,
+		ServiceRate:  p.ServiceRate,
+	}
+
+	for _, line := range lines {
+		result.Subtotal += line.Price * int64(line.Qty)
+	}
+	result.Discount = min(max(discount, 0), result.Subtotal)
+
+	base := result.Subtotal - result.Discount
+	result.ServiceCharge = percentOf(base, p.ServiceRate)
+
+	total := base + result.ServiceCharge
+	taxBase := base
+	if p.TaxOnService {
+		taxBase = total
+	}
+
+	if p.TaxInclusive {
+		// pajak sudah ada di dalam harga, hanya dipisahkan untuk laporan
+		result.Tax = taxBase - divRound(taxBase*10000, 10000+p.TaxRate)
+	} else {
+		result.Tax = percentOf(taxBase, p.TaxRate)
+		total += result.Tax
+	}
+
+	result.DeliveryFee = max(deliveryFee, 0)
+	total += result.DeliveryFee
+
+	result.Total = p.round(total)
+	result.Rounding = result.Total - total
+
+	return result
+}
+
+// MidtransItems item_details untuk Midtrans, jumlah price*qty selalu sama dengan Total (gross amount)
+func (r *PricingResult) MidtransItems() []midtrans.ItemDetails {
+	items := make([]midtrans.ItemDetails, 0, len(r.Lines)+4)
+	for _, line := range r.Lines {
+		items = append(items, midtrans.ItemDetails{
+			ID:    line.ID,
+			Name:  truncateItemName(line.Name),
+			Price: line.Price,
+			Qty:   int32(line.Qty),
+		})
+	}
+
+	extra := []struct {
+		id, name string
+		amount   int64
+	}{
+		{"DISCOUNT", "Discount", -r.Discount},
+		{"SERVICE", "Service Charge", r.ServiceCharge},
+		{"TAX", "Tax", r.Tax},
+		{"DELIVERY", "Delivery Fee", r.DeliveryFee},
+		{"ROUNDING", "Rounding", r.Rounding},
+	}
+	for _, e := range extra {
+		// pajak inclusive sudah ada di harga item, tidak boleh ditambah lagi
+		if e.amount == 0 || (e.id == "TAX" && r.TaxInclusive) {
+			continue
+		}
+		items = append(items, midtrans.ItemDetails{ID: e.id, Name: e.name, Price: e.amount, Qty: 1})
+	}
+
+	return items
+}
+
+func (p *PricingEngine) round(amount int64) int64 {
+	unit := p.RoundingUnit
+	if unit <= 1 {
+		return amount
+	}
+
+	switch p.Rounding {
+	case RoundingUp:
+		return (amount + unit - 1) / unit * unit
+	case RoundingDown:
+		return amount / unit * unit
+	case RoundingNearest:
+		return (amount + unit/2) / unit * unit
+	default:
+		return amount
+	}
+}
+
+// percentOf amount * rate (basis point), dibulatkan half-up
+func percentOf(amount, rate int64) int64 {
+	return divRound(amount*rate, 10000)
+}
+
+func divRound(a, b int64) int64 {
+	if b == 0 {
+		return 0
+	}
+	return (a + b/2) / b
+}
+
+// nama item Midtrans maksimal 50 karakter
+func truncateItemName(name string) string {
+	runes := []rune(name)
+	if len(runes) > 50 {
+		return string(runes[:50])
+	}
+	return name
+}
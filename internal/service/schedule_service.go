@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+	"time"
+)
+
+// OrderSchedule aturan pre-order: waktu ambil harus di dalam jam buka toko, minimal LeadTime dari sekarang
+// dan maksimal MaxDays ke depan. Order baru masuk antrian barista PrepWindow sebelum waktu ambil.
+type OrderSchedule struct {
+	OpenTime   string // HH:MM jam toko
+	CloseTime  string // HH:MM jam toko, waktu ambil paling lambat
+	Location   *time.Location
+	LeadTime   time.Duration
+	MaxDays    int
+	PrepWindow time.Duration
+}
+
+// Check validasi waktu ambil, error berisi pesan yang bisa langsung ditampilkan ke customer
+func (s *OrderSchedule) Check(at, now time.Time) error {
+	if at.Before(now.Add(s.LeadTime)) {
+		return fmt.Errorf("scheduled_for must be at least %d minutes from now", int(s.LeadTime.Minutes()))
+	}
+	if at.After(now.AddDate(0, 0, s.MaxDays)) {
+		return fmt.Errorf("scheduled_for must be within %d day(s) from now", s.MaxDays)
+	}
+
+	clock := at.In(s.Location).Format("15:04:05")
+	if clock < s.OpenTime+":00" || clock > s.CloseTime+":00" {
+		return fmt.Errorf("scheduled_for must be within store hours %s - %s", s.OpenTime, s.CloseTime)
+	}
+	return nil
+}
+
+// ReleaseAt waktu order pre-order mulai tampil di antrian barista
+func (s *OrderSchedule) ReleaseAt(scheduledFor time.Time) time.Time {
+	return scheduledFor.Add(-s.PrepWindow)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/notification"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
 	"github.com/sirupsen/logrus"
@@ -23,15 +24,17 @@ type CustomerUseCase struct {
 	Log                *logrus.Logger
 	Validator          *utils.Validator
 	CustomerRepository *repository.CustomerRepository
+	Mail               *notification.Dispatcher
 }
 
 func NewCustomerUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
-	customerRepository *repository.CustomerRepository) *CustomerUseCase {
+	customerRepository *repository.CustomerRepository, mail *notification.Dispatcher) *CustomerUseCase {
 	return &CustomerUseCase{
 		DB:                 db,
 		Log:                logger,
 		Validator:          validator,
 		CustomerRepository: customerRepository,
+		Mail:               mail,
 	}
 }
 
@@ -96,6 +99,8 @@ func (c *CustomerUseCase) Create(ctx context.Context, request *model.RegisterCus
 		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
 	}
 
+	c.Mail.SendWelcome(user)
+
 	return nil
 }
 
@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/realtime"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// KitchenUseCase antrian order untuk layar barista. Perpindahan status tetap lewat OrderUseCase
+// supaya history, email & event realtime sama dengan perubahan dari CMS.
+type KitchenUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	Validator       *utils.Validator
+	OrderRepository *repository.OrderRepository
+	OrderUseCase    *OrderUseCase
+	Events          *realtime.OrderBroker
+}
+
+func NewKitchenUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
+	orderRepository *repository.OrderRepository, orderUseCase *OrderUseCase, events *realtime.OrderBroker) *KitchenUseCase {
+	return &KitchenUseCase{
+		DB:              db,
+		Log:             logger,
+		Validator:       validator,
+		OrderRepository: orderRepository,
+		OrderUseCase:    orderUseCase,
+		Events:          events,
+	}
+}
+
+func (k *KitchenUseCase) FindQueue(ctx context.Context) (*model.KitchenQueueResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	orders, err := k.OrderRepository.FindKitchenQueue(k.DB.WithContext(ctx))
+	if err != nil {
+		k.Log.Warnf("Failed find kitchen queue from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	queue := &model.KitchenQueueResponse{
+		New:       []model.KitchenOrderResponse{},
+		Preparing: []model.KitchenOrderResponse{},
+		Ready:     []model.KitchenOrderResponse{},
+	}
+	for _, order := range orders {
+		response := converter.OrderResponseToKitchen(converter.OrderToResponse(&order))
+		switch response.Queue {
+		case model.KitchenQueueNew:
+			queue.New = append(queue.New, *response)
+		case model.KitchenQueuePreparing:
+			queue.Preparing = append(queue.Preparing, *response)
+		case model.KitchenQueueReady:
+			queue.Ready = append(queue.Ready, *response)
+		}
+	}
+
+	return queue, nil
+}
+
+// Move pindahkan order ke antrian berikutnya, urutan status dijaga state machine order
+func (k *KitchenUseCase) Move(ctx context.Context, orderID, userID string, request *model.MoveKitchenOrderRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := k.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(k.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	if _, err := uuid.Parse(orderID); err != nil {
+		return utils.ErrNotFound
+	}
+
+	order := &entity.Order{}
+	if _, err := k.OrderRepository.FindById(k.DB.WithContext(ctx), order, orderID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			k.Log.Infof("order not found, id=%s", orderID)
+			return utils.ErrNotFound
+		}
+		k.Log.Warnf("Failed find order from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	// ✅ Pre-order belum tampil di layar barista sampai masuk prep window, jadi belum boleh dipindah dari sini
+	if order.ScheduledFor != nil && order.KitchenReleasedAt == nil {
+		return fmt.Errorf("%w: scheduled order %s is not released to the kitchen yet", utils.ErrConflict, order.InvoiceNumber)
+	}
+
+	reason := request.Reason
+	if reason == "" {
+		reason = "moved to " + request.Status + " from kitchen display"
+	}
+
+	return k.OrderUseCase.UpdateStatus(ctx, orderID, userID, &model.UpdateOrderStatusRequest{
+		Status: request.Status,
+		Reason: reason,
+	})
+}
+
+// Subscribe event order untuk satu koneksi layar barista, wajib di-Close setelah koneksi selesai
+func (k *KitchenUseCase) Subscribe() *realtime.Subscription {
+	return k.Events.Subscribe()
+}
+
+// ToKitchenEvent ubah event order jadi event layar barista, nil kalau perubahan tidak menyentuh antrian
+// (ex: order pending, atau pre-order yang dibayar tapi belum masuk prep window)
+func (k *KitchenUseCase) ToKitchenEvent(event *realtime.OrderEvent) *model.KitchenEventResponse {
+	if event.Order == nil {
+		return nil
+	}
+
+	order := converter.OrderResponseToKitchen(event.Order)
+	switch {
+	case order.Queue != "":
+		return &model.KitchenEventResponse{Action: model.KitchenActionUpsert, Order: order}
+	case converter.KitchenQueue(event.FromStatus) != "":
+		return &model.KitchenEventResponse{Action: model.KitchenActionRemove, Order: order}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,329 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type LoyaltyUseCase struct {
+	DB                 *gorm.DB
+	Log                *logrus.Logger
+	Validator          *utils.Validator
+	LoyaltyRepository  *repository.LoyaltyRepository
+	CustomerRepository *repository.CustomerRepository
+	Program            *service.LoyaltyProgram
+}
+
+func NewLoyaltyUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
+	loyaltyRepository *repository.LoyaltyRepository, customerRepository *repository.CustomerRepository,
+	program *service.LoyaltyProgram) *LoyaltyUseCase {
+	return &LoyaltyUseCase{
+		DB:                 db,
+		Log:                logger,
+		Validator:          validator,
+		LoyaltyRepository:  loyaltyRepository,
+		CustomerRepository: customerRepository,
+		Program:            program,
+	}
+}
+
+func (l *LoyaltyUseCase) FindByCustomerID(ctx context.Context, customerID string) (*model.LoyaltyResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	id, err := uuid.Parse(customerID)
+	if err != nil {
+		return nil, utils.ErrUnauthorized
+	}
+
+	return l.findAccount(ctx, id)
+}
+
+// FindByCustomerIDForCMS sama dengan FindByCustomerID, tapi customer harus ada
+func (l *LoyaltyUseCase) FindByCustomerIDForCMS(ctx context.Context, customerID string) (*model.LoyaltyResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	id, err := l.findCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return l.findAccount(ctx, id)
+}
+
+func (l *LoyaltyUseCase) FindTransactions(ctx context.Context, customerID string, pagination *utils.PaginationRequest) ([]model.LoyaltyTransactionResponse, *utils.PaginationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	id, err := uuid.Parse(customerID)
+	if err != nil {
+		return nil, nil, utils.ErrUnauthorized
+	}
+
+	responses, paginationRes, err := l.findTransactions(ctx, id, pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range responses {
+		responses[i].Actor = ""
+	}
+
+	return responses, paginationRes, nil
+}
+
+func (l *LoyaltyUseCase) FindTransactionsForCMS(ctx context.Context, customerID string, pagination *utils.PaginationRequest) ([]model.LoyaltyTransactionResponse, *utils.PaginationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	id, err := l.findCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return l.findTransactions(ctx, id, pagination)
+}
+
+// Adjust tambah / kurangi poin manual dari CMS, alasan wajib diisi untuk audit
+func (l *LoyaltyUseCase) Adjust(ctx context.Context, customerID, userID string, request *model.AdjustLoyaltyRequest) (*model.LoyaltyTransactionResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := l.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(l.Validator.Translator))
+			}
+			return nil, fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return nil, fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	id, err := l.findCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := l.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	account, err := l.LoyaltyRepository.FindOrCreateForUpdate(tx, id)
+	if err != nil {
+		l.Log.Warnf("Failed find loyalty account from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	transaction := &entity.LoyaltyTransaction{
+		Type:   entity.LoyaltyTypeAdjust,
+		Points: request.Points,
+		Reason: request.Reason,
+		Actor:  "cms:" + userID,
+	}
+	if request.Points > 0 {
+		transaction.ExpiresAt = l.Program.ExpiresAt(time.Now())
+	}
+	if err := postLoyaltyPoints(tx, l.LoyaltyRepository, account, transaction); err != nil {
+		if !errors.Is(err, utils.ErrInsufficientBalance) {
+			l.Log.Warnf("Failed adjust loyalty points : %+v", err)
+		}
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		l.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return converter.LoyaltyTransactionToResponse(transaction), nil
+}
+
+// ExpirePoints hanguskan lot poin yang sudah lewat tanggal expire, dipanggil worker
+func (l *LoyaltyUseCase) ExpirePoints(ctx context.Context, limit int) (int, error) {
+	now := time.Now()
+	accountIDs, err := l.LoyaltyRepository.FindExpiredAccountIDs(l.DB.WithContext(ctx), now, limit)
+	if err != nil {
+		l.Log.Warnf("Failed find expired loyalty points : %+v", err)
+		return 0, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	expired := 0
+	for _, accountID := range accountIDs {
+		if err := l.expireAccount(ctx, accountID, now); err != nil {
+			l.Log.Warnf("Failed expire loyalty points, account=%s : %+v", accountID, err)
+			continue
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+func (l *LoyaltyUseCase) expireAccount(ctx context.Context, accountID uuid.UUID, now time.Time) error {
+	tx := l.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	account, err := l.LoyaltyRepository.FindByIDForUpdate(tx, accountID)
+	if err != nil {
+		return err
+	}
+
+	lots, err := l.LoyaltyRepository.FindExpiredLots(tx, account.ID, now)
+	if err != nil {
+		return err
+	}
+
+	var points int64
+	for i := range lots {
+		points += lots[i].Remaining
+		lots[i].Remaining = 0
+		if err := l.LoyaltyRepository.UpdateTransaction(tx, &lots[i]); err != nil {
+			return err
+		}
+	}
+	if points == 0 {
+		return nil
+	}
+
+	account.Balance -= points
+	transaction := &entity.LoyaltyTransaction{
+		AccountID:    account.ID,
+		Type:         entity.LoyaltyTypeExpire,
+		Points:       -points,
+		BalanceAfter: account.Balance,
+		Reason:       fmt.Sprintf("%d point(s) expired", points),
+		Actor:        entity.ActorLoyaltyExpiryWorker,
+	}
+	if err := l.LoyaltyRepository.Update(tx, account); err != nil {
+		return err
+	}
+	if err := l.LoyaltyRepository.CreateTransaction(tx, transaction); err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+func (l *LoyaltyUseCase) findAccount(ctx context.Context, customerID uuid.UUID) (*model.LoyaltyResponse, error) {
+	account, err := l.LoyaltyRepository.FindByCustomerID(l.DB.WithContext(ctx), customerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// belum pernah dapat poin, saldo 0
+			return converter.LoyaltyToResponse(&entity.LoyaltyAccount{CustomerID: customerID}, l.Program.PointValue), nil
+		}
+		l.Log.Warnf("Failed find loyalty account from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return converter.LoyaltyToResponse(account, l.Program.PointValue), nil
+}
+
+func (l *LoyaltyUseCase) findTransactions(ctx context.Context, customerID uuid.UUID, pagination *utils.PaginationRequest) ([]model.LoyaltyTransactionResponse, *utils.PaginationResponse, error) {
+	paginationRes := &utils.PaginationResponse{
+		Page:    pagination.Page,
+		Limit:   pagination.Limit,
+		OrderBy: "created_at",
+		SortBy:  "desc",
+	}
+
+	account, err := l.LoyaltyRepository.FindByCustomerID(l.DB.WithContext(ctx), customerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return []model.LoyaltyTransactionResponse{}, paginationRes, nil
+		}
+		l.Log.Warnf("Failed find loyalty account from database : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	transactions, total, err := l.LoyaltyRepository.FindTransactions(l.DB.WithContext(ctx), account.ID, pagination)
+	if err != nil {
+		l.Log.Warnf("Failed find loyalty transactions from database : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	responses := make([]model.LoyaltyTransactionResponse, len(transactions))
+	for i, transaction := range transactions {
+		responses[i] = *converter.LoyaltyTransactionToResponse(&transaction)
+	}
+
+	paginationRes.TotalData = total
+	paginationRes.TotalPage = int((total + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	return responses, paginationRes, nil
+}
+
+func (l *LoyaltyUseCase) findCustomerID(ctx context.Context, customerID string) (uuid.UUID, error) {
+	id, err := uuid.Parse(customerID)
+	if err != nil {
+		return uuid.Nil, utils.ErrNotFound
+	}
+
+	if _, err := l.CustomerRepository.FindById(l.DB.WithContext(ctx), &entity.Customer{}, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			l.Log.Infof("customer not found, id=%s", customerID)
+			return uuid.Nil, utils.ErrNotFound
+		}
+		l.Log.Warnf("Failed find customer from database : %+v", err)
+		return uuid.Nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return id, nil
+}
+
+// postLoyaltyPoints catat mutasi poin di akun yang sudah di-lock caller. Poin masuk jadi lot baru,
+// poin keluar mengurangi lot yang paling cepat expire dulu supaya poin lama terpakai duluan.
+func postLoyaltyPoints(tx *gorm.DB, loyaltyRepository *repository.LoyaltyRepository, account *entity.LoyaltyAccount, transaction *entity.LoyaltyTransaction) error {
+	switch {
+	case transaction.Points > 0:
+		transaction.Remaining = transaction.Points
+	case transaction.Points < 0:
+		if account.Balance < -transaction.Points {
+			return fmt.Errorf("%w: loyalty points balance %d, required %d", utils.ErrInsufficientBalance, account.Balance, -transaction.Points)
+		}
+
+		lots, err := loyaltyRepository.FindOpenLots(tx, account.ID)
+		if err != nil {
+			return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+		}
+		needed := -transaction.Points
+		for i := 0; i < len(lots) && needed > 0; i++ {
+			used := min(lots[i].Remaining, needed)
+			lots[i].Remaining -= used
+			needed -= used
+			if err := loyaltyRepository.UpdateTransaction(tx, &lots[i]); err != nil {
+				return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+			}
+		}
+	default:
+		return nil
+	}
+
+	account.Balance += transaction.Points
+	transaction.AccountID = account.ID
+	transaction.BalanceAfter = account.Balance
+	if err := loyaltyRepository.Update(tx, account); err != nil {
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if err := loyaltyRepository.CreateTransaction(tx, transaction); err != nil {
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
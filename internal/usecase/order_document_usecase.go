@@ -0,0 +1,218 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	DocumentInvoice = "invoice"
+	DocumentReceipt = "receipt" // hanya untuk order yang sudah dibayar
+)
+
+const documentTimeFormat = "02 Jan 2006 15:04 MST"
+
+type OrderDocumentUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	OrderRepository *repository.OrderRepository
+	Store           *utils.StoreInfo
+	Location        *time.Location
+}
+
+func NewOrderDocumentUseCase(db *gorm.DB, logger *logrus.Logger, orderRepository *repository.OrderRepository,
+	store *utils.StoreInfo, location *time.Location) *OrderDocumentUseCase {
+	return &OrderDocumentUseCase{
+		DB:              db,
+		Log:             logger,
+		OrderRepository: orderRepository,
+		Store:           store,
+		Location:        location,
+	}
+}
+
+// Render invoice / receipt order mana saja, untuk CMS
+func (d *OrderDocumentUseCase) Render(ctx context.Context, orderID, kind string) (*model.OrderDocumentResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	order, err := d.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return d.render(order, kind)
+}
+
+// RenderForCustomer sama seperti Render tapi hanya untuk order milik customer sendiri
+func (d *OrderDocumentUseCase) RenderForCustomer(ctx context.Context, customerID, orderID, kind string) (*model.OrderDocumentResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(customerID); err != nil {
+		return nil, utils.ErrUnauthorized
+	}
+
+	order, err := d.findOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	// order milik customer lain dianggap tidak ada
+	if order.UserID.String() != customerID {
+		d.Log.Infof("order not found, id=%s customer=%s", orderID, customerID)
+		return nil, utils.ErrNotFound
+	}
+	return d.render(order, kind)
+}
+
+func (d *OrderDocumentUseCase) findOrder(ctx context.Context, orderID string) (*entity.Order, error) {
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, utils.ErrNotFound
+	}
+
+	order, err := d.OrderRepository.FindForDocument(d.DB.WithContext(ctx), orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			d.Log.Infof("order not found, id=%s", orderID)
+			return nil, utils.ErrNotFound
+		}
+		d.Log.Warnf("Failed find order from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	return order, nil
+}
+
+func (d *OrderDocumentUseCase) render(order *entity.Order, kind string) (*model.OrderDocumentResponse, error) {
+	paidAt := paidAtFromHistory(order)
+
+	var title string
+	switch kind {
+	case DocumentInvoice:
+		title = "INVOICE"
+	case DocumentReceipt:
+		if paidAt == nil {
+			return nil, fmt.Errorf("%w: receipt is only available for paid orders", utils.ErrConflict)
+		}
+		title = "RECEIPT"
+	default:
+		return nil, fmt.Errorf("%w: unknown document type %s", utils.ErrValidation, kind)
+	}
+
+	// data customer dari snapshot checkout, bukan profil customer terbaru
+	doc := &utils.OrderDocument{
+		Title:           title,
+		Store:           *d.Store,
+		InvoiceNumber:   order.InvoiceNumber,
+		Status:          order.Status,
+		IssuedAt:        order.CreatedAt.In(d.Location).Format(documentTimeFormat),
+		CustomerName:    order.CustomerName,
+		CustomerEmail:   order.CustomerEmail,
+		CustomerPhone:   order.CustomerPhone,
+		ShippingAddress: order.ShippingAddr,
+		PaymentMethod:   documentPaymentMethod(order),
+		Total:           order.Amount,
+	}
+	if paidAt != nil {
+		doc.PaidAt = paidAt.In(d.Location).Format(documentTimeFormat)
+	}
+
+	// pakai snapshot nama & harga di order item, bukan data product terbaru
+	for _, item := range order.OrderItems {
+		doc.Items = append(doc.Items, utils.OrderDocumentItem{
+			Name:     item.ProductName,
+			Qty:      item.Qty,
+			Price:    item.Price,
+			Subtotal: item.Subtotal,
+		})
+	}
+
+	doc.Summary = append(doc.Summary, utils.OrderDocumentLine{Label: "Subtotal", Amount: order.Subtotal})
+	// discount_amount sudah termasuk potongan poin, ditampilkan terpisah
+	if discount := order.DiscountAmount - order.PointsDiscount; discount > 0 {
+		label := "Discount"
+		if order.VoucherCode != "" {
+			label += " (" + order.VoucherCode + ")"
+		}
+		doc.Summary = append(doc.Summary, utils.OrderDocumentLine{Label: label, Amount: -discount})
+	}
+	if order.PointsDiscount > 0 {
+		doc.Summary = append(doc.Summary, utils.OrderDocumentLine{
+			Label:  fmt.Sprintf("Points (%d pts)", order.PointsRedeemed),
+			Amount: -order.PointsDiscount,
+		})
+	}
+	if order.ServiceCharge > 0 {
+		doc.Summary = append(doc.Summary, utils.OrderDocumentLine{
+			Label:  "Service Charge " + formatRate(order.ServiceRate),
+			Amount: order.ServiceCharge,
+		})
+	}
+	if order.TaxAmount > 0 && !order.TaxInclusive {
+		doc.Summary = append(doc.Summary, utils.OrderDocumentLine{Label: "Tax " + formatRate(order.TaxRate), Amount: order.TaxAmount})
+	}
+	if order.DeliveryFee > 0 {
+		doc.Summary = append(doc.Summary, utils.OrderDocumentLine{Label: "Delivery Fee", Amount: order.DeliveryFee})
+	}
+	if order.RoundingAmount != 0 {
+		doc.Summary = append(doc.Summary, utils.OrderDocumentLine{Label: "Rounding", Amount: order.RoundingAmount})
+	}
+
+	if order.TaxAmount > 0 && order.TaxInclusive {
+		doc.Footer = append(doc.Footer, "Prices include tax "+formatRate(order.TaxRate)+": "+utils.FormatRupiah(order.TaxAmount))
+	}
+	if order.RefundedAmount > 0 {
+		doc.Footer = append(doc.Footer, "Refunded: "+utils.FormatRupiah(order.RefundedAmount))
+	}
+	if kind == DocumentInvoice && paidAt == nil && order.ExpiredAt != nil && order.Status == entity.OrderStatusPending {
+		doc.Footer = append(doc.Footer, "Please complete payment before "+order.ExpiredAt.In(d.Location).Format(documentTimeFormat))
+	}
+	doc.Footer = append(doc.Footer, "Thank you for your order.")
+
+	return &model.OrderDocumentResponse{
+		FileName: order.InvoiceNumber + "-" + kind + ".pdf",
+		Content:  utils.RenderOrderDocument(doc),
+	}, nil
+}
+
+// paidAtFromHistory waktu pertama kali order jadi paid
+func paidAtFromHistory(order *entity.Order) *time.Time {
+	for _, history := range order.Histories {
+		if history.ToStatus == entity.OrderStatusPaid {
+			paidAt := history.CreatedAt
+			return &paidAt
+		}
+	}
+	return nil
+}
+
+func documentPaymentMethod(order *entity.Order) string {
+	method := order.PaymentMethod
+	if order.PaymentType != "" && order.PaymentType != method {
+		if method != "" {
+			method += " / "
+		}
+		method += order.PaymentType
+	}
+	if order.VABank != "" {
+		method += " (" + order.VABank + ")"
+	}
+	if method == "" {
+		method = "-"
+	}
+	return method
+}
+
+// formatRate basis point ke persen, ex: 1100 -> "11%", 250 -> "2.5%"
+func formatRate(rate int64) string {
+	return strconv.FormatFloat(float64(rate)/100, 'f', -1, 64) + "%"
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+)
+
+// go test ./internal/usecase -run TestRenderOrderDocument -update
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata")
+
+func TestRenderOrderDocument(t *testing.T) {
+	d := &OrderDocumentUseCase{
+		Store: &utils.StoreInfo{
+			Name:    "Daily Coffee",
+			Address: "Jl. Kopi No. 1, Jakarta",
+			Phone:   "021-5550123",
+			Email:   "hello@dailycoffee.id",
+			TaxID:   "01.234.567.8-901.000",
+		},
+		Location: time.FixedZone("WIB", 7*60*60),
+	}
+
+	tests := []struct {
+		name  string
+		kind  string
+		order *entity.Order
+	}{
+		{name: "invoice", kind: DocumentInvoice, order: documentFixture(func(o *entity.Order) {
+			o.Status = entity.OrderStatusPending
+			o.Histories = o.Histories[:1]
+			expiredAt := o.CreatedAt.Add(24 * time.Hour)
+			o.ExpiredAt = &expiredAt
+		})},
+		{name: "receipt", kind: DocumentReceipt, order: documentFixture(nil)},
+		{name: "tax_inclusive", kind: DocumentReceipt, order: documentFixture(func(o *entity.Order) {
+			o.TaxInclusive = true
+			o.TaxAmount = 6_000
+			o.RoundingAmount = 0
+			o.Amount = o.Subtotal - o.DiscountAmount + o.ServiceCharge + o.DeliveryFee
+		})},
+		{name: "points", kind: DocumentReceipt, order: documentFixture(func(o *entity.Order) {
+			o.PointsRedeemed = 50
+			o.PointsDiscount = 5_000
+			o.DiscountAmount += o.PointsDiscount
+			o.ServiceCharge = 2_720
+			o.TaxAmount = 6_283
+			o.RoundingAmount = -3
+			o.Amount = 73_400
+		})},
+		{name: "refunded", kind: DocumentReceipt, order: documentFixture(func(o *entity.Order) {
+			o.Status = entity.OrderStatusRefunded
+			o.RefundedAmount = 28_000
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := d.render(tt.order, tt.kind)
+			if err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if want := tt.order.InvoiceNumber + "-" + tt.kind + ".pdf"; response.FileName != want {
+				t.Errorf("file name = %q, want %q", response.FileName, want)
+			}
+
+			golden := filepath.Join("testdata", "order_document", tt.name+".pdf")
+			if *updateGolden {
+				if err := os.MkdirAll(filepath.Dir(golden), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(golden, response.Content, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("read golden file (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(response.Content, want) {
+				t.Errorf("%s does not match rendered document, run with -update if the change is intended", golden)
+			}
+		})
+	}
+}
+
+func TestRenderOrderDocumentReceiptRequiresPayment(t *testing.T) {
+	d := &OrderDocumentUseCase{Store: &utils.StoreInfo{Name: "Daily Coffee"}, Location: time.UTC}
+	order := documentFixture(func(o *entity.Order) {
+		o.Status = entity.OrderStatusPending
+		o.Histories = o.Histories[:1]
+	})
+
+	if _, err := d.render(order, DocumentReceipt); err == nil {
+		t.Fatal("expected error for receipt of unpaid order")
+	}
+}
+
+// documentFixture order paid: 2 item, voucher, service charge, pajak exclusive, ongkir & pembulatan
+func documentFixture(modify func(o *entity.Order)) *entity.Order {
+	createdAt := time.Date(2025, time.September, 9, 2, 15, 0, 0, time.UTC)
+	order := &entity.Order{
+		ID:             uuid.MustParse("8f1c2d3e-4b5a-4c6d-8e7f-9a0b1c2d3e4f"),
+		UserID:         uuid.MustParse("1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d"),
+		CustomerName:   "Budi Santoso",
+		CustomerEmail:  "budi@example.com",
+		CustomerPhone:  "081234567890",
+		InvoiceNumber:  "INV-20250909-0003",
+		Status:         entity.OrderStatusPaid,
+		Subtotal:       66_000,
+		DiscountAmount: 6_600,
+		VoucherCode:    "HEMAT10",
+		ServiceCharge:  2_970,
+		TaxAmount:      6_861,
+		DeliveryFee:    10_000,
+		RoundingAmount: -31,
+		TaxRate:        1100,
+		ServiceRate:    500,
+		Amount:         79_200,
+		PaymentMethod:  "bank_transfer",
+		PaymentType:    "bank_transfer",
+		VABank:         "bca",
+		ShippingAddr:   "Jl. Melati No. 12, Jakarta Selatan",
+		OrderItems: []entity.OrderItem{
+			{ProductName: "Kopi Susu Gula Aren", Qty: 2, Price: 25_000, Subtotal: 50_000},
+			{ProductName: "Croissant Butter", Qty: 1, Price: 16_000, Subtotal: 16_000},
+		},
+		Histories: []entity.OrderStatusHistory{
+			{ToStatus: entity.OrderStatusPending, CreatedAt: createdAt},
+			{FromStatus: entity.OrderStatusPending, ToStatus: entity.OrderStatusPaid, CreatedAt: createdAt.Add(5 * time.Minute)},
+		},
+		CreatedAt: createdAt,
+	}
+	if modify != nil {
+		modify(order)
+	}
+	return order
+}
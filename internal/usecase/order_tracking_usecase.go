@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/realtime"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// OrderTrackingUseCase stream status order untuk customer, event diambil dari broker yang sama dengan layar barista
+type OrderTrackingUseCase struct {
+	DB              *gorm.DB
+	Log             *logrus.Logger
+	Validator       *utils.Validator
+	OrderRepository *repository.OrderRepository
+	Events          *realtime.OrderBroker
+}
+
+func NewOrderTrackingUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
+	orderRepository *repository.OrderRepository, events *realtime.OrderBroker) *OrderTrackingUseCase {
+	return &OrderTrackingUseCase{
+		DB:              db,
+		Log:             logger,
+		Validator:       validator,
+		OrderRepository: orderRepository,
+		Events:          events,
+	}
+}
+
+// OrderTracking satu koneksi stream customer. Urutan kirim: Replay (event yang terlewat sejak Last-Event-ID),
+// Snapshot (status terbaru tiap order), lalu event live dari Subscription yang lolos Accept.
+type OrderTracking struct {
+	Snapshot     []model.OrderStatusEventResponse
+	Replay       []*realtime.OrderEvent
+	Subscription *realtime.Subscription
+	customerID   string
+	orderIDs     map[string]bool
+	replayed     map[int64]bool
+}
+
+// Accept cek apakah event live perlu dikirim ke koneksi ini. Event yang sudah terkirim lewat replay dilewati,
+// begitu juga event tanpa perubahan status (ex: pre-order masuk antrian barista).
+func (t *OrderTracking) Accept(event *realtime.OrderEvent) bool {
+	return event.CustomerID == t.customerID && t.orderIDs[event.OrderID] && !t.replayed[event.ID] &&
+		event.FromStatus != event.Status
+}
+
+// Track validasi order yang diminta memang milik customer lalu siapkan stream-nya.
+// Subscription wajib di-Close setelah koneksi selesai.
+func (o *OrderTrackingUseCase) Track(ctx context.Context, customerID string, request *model.TrackOrderRequest) (*OrderTracking, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(customerID); err != nil {
+		return nil, utils.ErrUnauthorized
+	}
+
+	err := o.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(o.Validator.Translator))
+			}
+			return nil, fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return nil, fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	tracking := &OrderTracking{
+		customerID: customerID,
+		orderIDs:   make(map[string]bool, len(request.OrderIDs)),
+		replayed:   make(map[int64]bool),
+	}
+	ids := make([]uuid.UUID, 0, len(request.OrderIDs))
+	for _, orderID := range request.OrderIDs {
+		id := utils.MustParseUUID(orderID)
+		if tracking.orderIDs[id.String()] {
+			continue
+		}
+		tracking.orderIDs[id.String()] = true
+		ids = append(ids, id)
+	}
+
+	// ✅ Subscribe sebelum baca replay & snapshot, supaya perubahan di antaranya tidak hilang
+	tracking.Subscription = o.Events.Subscribe()
+
+	if request.LastEventID != "" {
+		lastEventID, _ := strconv.ParseInt(request.LastEventID, 10, 64)
+		events, err := o.Events.Replay(ctx, customerID, lastEventID)
+		if err != nil {
+			// replay hanya pelengkap, snapshot di bawah tetap berisi status terbaru
+			o.Log.Warnf("Failed replay order events, customer=%s : %+v", customerID, err)
+		}
+		for _, event := range events {
+			if !tracking.orderIDs[event.OrderID] || event.FromStatus == event.Status {
+				continue
+			}
+			tracking.Replay = append(tracking.Replay, event)
+			tracking.replayed[event.ID] = true
+		}
+	}
+
+	orders, err := o.OrderRepository.FindByIDsAndUserID(o.DB.WithContext(ctx), ids, customerID)
+	if err != nil {
+		tracking.Subscription.Close()
+		o.Log.Warnf("Failed find orders from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if len(orders) != len(ids) {
+		tracking.Subscription.Close()
+		return nil, utils.ErrNotFound
+	}
+
+	tracking.Snapshot = make([]model.OrderStatusEventResponse, len(orders))
+	for i, order := range orders {
+		tracking.Snapshot[i] = *converter.OrderToStatusEvent(&order)
+	}
+
+	return tracking, nil
+}
@@ -2,42 +2,123 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 	"github.com/midtrans/midtrans-go"
+	"github.com/midtrans/midtrans-go/coreapi"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/notification"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/realtime"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
 	"github.com/sirupsen/logrus"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
+const (
+	scheduledDefaultDays = 7  // default rentang list pre-order CMS
+	scheduledMaxDays     = 31 // rentang maksimal sekali request
+)
+
 type OrderUseCase struct {
-	DB              *gorm.DB
-	Log             *logrus.Logger
-	Validator       *utils.Validator
-	OrderRepository *repository.OrderRepository
-	Midtrans        *service.MidtransService
+	DB                   *gorm.DB
+	Log                  *logrus.Logger
+	Validator            *utils.Validator
+	OrderRepository      *repository.OrderRepository
+	PaymentLogRepository *repository.PaymentLogRepository
+	HistoryRepository    *repository.OrderStatusHistoryRepository
+	ProductRepository    *repository.ProductRepository
+	RefundRepository     *repository.RefundRepository
+	VoucherRepository    *repository.VoucherRepository
+	ShippingZone         *repository.ShippingZoneRepository
+	PickupSlot           *repository.PickupSlotRepository
+	CustomerRepository   *repository.CustomerRepository
+	WalletRepository     *repository.WalletRepository
+	LoyaltyRepository    *repository.LoyaltyRepository
+	InvoiceCounter       *repository.InvoiceCounterRepository
+	InvoiceFormat        *utils.InvoiceFormat
+	Payment              service.PaymentProvider
+	CheckoutMode         string // default kalau request tidak memilih
+	Pricing              *service.PricingEngine
+	Loyalty              *service.LoyaltyProgram
+	Schedule             *service.OrderSchedule
+	Location             *time.Location // timezone toko, untuk tanggal pickup & filter tanggal order
+	Mail                 *notification.Dispatcher
+	Events               *realtime.OrderBroker
 }
 
 func NewOrderUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
-	orderRepository *repository.OrderRepository, midtrans *service.MidtransService) *OrderUseCase {
+	orderRepository *repository.OrderRepository, paymentLogRepository *repository.PaymentLogRepository,
+	historyRepository *repository.OrderStatusHistoryRepository, productRepository *repository.ProductRepository,
+	refundRepository *repository.RefundRepository, voucherRepository *repository.VoucherRepository,
+	shippingZone *repository.ShippingZoneRepository, pickupSlot *repository.PickupSlotRepository,
+	customerRepository *repository.CustomerRepository, walletRepository *repository.WalletRepository, loyaltyRepository *repository.LoyaltyRepository,
+	invoiceCounter *repository.InvoiceCounterRepository, invoiceFormat *utils.InvoiceFormat,
+	payment service.PaymentProvider, checkoutMode string, pricing *service.PricingEngine,
+	loyalty *service.LoyaltyProgram, schedule *service.OrderSchedule, location *time.Location, mail *notification.Dispatcher,
+	events *realtime.OrderBroker) *OrderUseCase {
 	return &OrderUseCase{
-		DB:              db,
-		Log:             logger,
-		Validator:       validator,
-		OrderRepository: orderRepository,
-		Midtrans:        midtrans,
+		DB:                   db,
+		Log:                  logger,
+		Validator:            validator,
+		OrderRepository:      orderRepository,
+		PaymentLogRepository: paymentLogRepository,
+		HistoryRepository:    historyRepository,
+		ProductRepository:    productRepository,
+		RefundRepository:     refundRepository,
+		VoucherRepository:    voucherRepository,
+		ShippingZone:         shippingZone,
+		PickupSlot:           pickupSlot,
+		CustomerRepository:   customerRepository,
+		WalletRepository:     walletRepository,
+		LoyaltyRepository:    loyaltyRepository,
+		InvoiceCounter:       invoiceCounter,
+		InvoiceFormat:        invoiceFormat,
+		Payment:              payment,
+		CheckoutMode:         checkoutMode,
+		Pricing:              pricing,
+		Loyalty:              loyalty,
+		Schedule:             schedule,
+		Location:             location,
+		Mail:                 mail,
+		Events:               events,
+	}
+}
+
+// Create order dari guest endpoint, customer_id dari body jadi tidak bisa bayar pakai wallet / poin loyalty
+// dan tidak bisa pakai voucher yang dibatasi per customer
+func (o *OrderUseCase) Create(ctx context.Context, request *model.CreateOrderRequest) (*model.CreateOrderResponse, error) {
+	if request.PaymentMethod == entity.PaymentMethodWallet {
+		return nil, fmt.Errorf("%w: login is required to pay with wallet", utils.ErrUnauthorized)
+	}
+	if request.RedeemPoints > 0 {
+		return nil, fmt.Errorf("%w: login is required to redeem loyalty points", utils.ErrUnauthorized)
+	}
+	return o.create(ctx, request, true)
+}
+
+// CreateForCustomer order dari customer yang login, customer_id selalu dari token
+func (o *OrderUseCase) CreateForCustomer(ctx context.Context, customerID string, request *model.CreateOrderRequest) (*model.CreateOrderResponse, error) {
+	if _, err := uuid.Parse(customerID); err != nil {
+		return nil, utils.ErrUnauthorized
 	}
+
+	request.CustomerID = customerID
+	return o.create(ctx, request, false)
 }
 
-func (o *OrderUseCase) Create(ctx context.Context, request *model.CreateOrderRequest) error {
+func (o *OrderUseCase) create(ctx context.Context, request *model.CreateOrderRequest, guest bool) (*model.CreateOrderResponse, error) {
 	tx := o.DB.WithContext(ctx).Begin()
 	defer tx.Rollback()
 
@@ -49,88 +130,1856 @@ func (o *OrderUseCase) Create(ctx context.Context, request *model.CreateOrderReq
 			for _, e := range validationErrors {
 				messages = append(messages, e.Translate(o.Validator.Translator))
 			}
-			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+			return nil, fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
 		}
-		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+		return nil, fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	checkoutMode := request.CheckoutMode
+	if checkoutMode == "" {
+		checkoutMode = o.CheckoutMode
+	}
+	if request.PaymentMethod == entity.PaymentMethodWallet {
+		// tidak lewat Midtrans, checkout mode tidak berlaku
+		checkoutMode = entity.CheckoutModeCore
+	}
+	if checkoutMode == entity.CheckoutModeCore && request.PaymentMethod == "" {
+		return nil, fmt.Errorf("%w: payment_method is required", utils.ErrValidation)
 	}
 
-	todayCount, _ := o.OrderRepository.GetTodayOrderCount(ctx, tx)
-	invoiceNumber := utils.GenerateInvoice(todayCount + 1)
+	invoiceNumber, err := o.nextInvoiceNumber(ctx)
+	if err != nil {
+		o.Log.Warnf("Failed generate invoice number : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	productIDs := make([]uuid.UUID, 0, len(request.Items))
+	for _, item := range request.Items {
+		productIDs = append(productIDs, utils.MustParseUUID(item.ProductID))
+	}
+
+	products, err := o.ProductRepository.FindByIDsForUpdate(tx, productIDs)
+	if err != nil {
+		o.Log.Warnf("Failed find products from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
 
-	// ✅ Hitung total
-	var totalAmount int64
+	productMap := make(map[uuid.UUID]entity.Product, len(products))
+	for _, product := range products {
+		productMap[product.ID] = product
+	}
+
+	// ✅ Hitung total pakai harga & nama dari database, bukan dari client
 	var orderItems []entity.OrderItem
+	var unavailable []string
+	var priceChanges []model.PriceChangedItem
+	requestedQty := make(map[uuid.UUID]int)
 	for _, item := range request.Items {
-		subtotal := item.Price * int64(item.Quantity)
-		totalAmount += subtotal
+		product, ok := productMap[utils.MustParseUUID(item.ProductID)]
+		if !ok || !product.IsActive {
+			unavailable = append(unavailable, item.ProductID)
+			continue
+		}
+
+		price := int64(product.Price)
+		if item.Price != 0 && item.Price != price {
+			priceChanges = append(priceChanges, model.PriceChangedItem{
+				ProductID:    product.ID.String(),
+				Name:         product.Name,
+				Price:        item.Price,
+				CurrentPrice: price,
+			})
+		}
+
+		subtotal := price * int64(item.Quantity)
+		requestedQty[product.ID] += item.Quantity
 
 		orderItems = append(orderItems, entity.OrderItem{
-			ProductID:   utils.MustParseUUID(item.ProductID),
-			ProductName: item.Name,
+			ProductID:   product.ID,
+			ProductName: product.Name,
 			Qty:         item.Quantity,
-			Price:       item.Price,
+			Price:       price,
 			Subtotal:    subtotal,
 		})
 	}
 
-	chargeReq, err := utils.BuildChargeReq(request, invoiceNumber, totalAmount)
-	if err != nil {
-		return fmt.Errorf("build charge req failed: %w", err)
+	if len(unavailable) > 0 {
+		return nil, fmt.Errorf("%w: product not available: %s", utils.ErrValidation, strings.Join(unavailable, ", "))
+	}
+	if len(priceChanges) > 0 {
+		return nil, &utils.PriceChangedError{Items: priceChanges}
 	}
 
-	resp, err := o.Midtrans.Charge(chargeReq)
-	if err != nil {
-		// cek apakah error dari midtrans
-		if midErr, ok := err.(*midtrans.Error); ok && midErr != nil {
-			o.Log.Errorf("Midtrans error: StatusCode=%v, Message=%v", midErr.StatusCode, midErr.Message)
-			return fmt.Errorf("%w: midtrans error: %s", utils.ErrPayment, midErr.Message)
+	// ✅ Cek & hold stok, row product sudah di-lock di atas
+	var shortItems []model.OutOfStockItem
+	for _, product := range products {
+		qty, ok := requestedQty[product.ID]
+		if !ok {
+			continue
+		}
+		available := product.Stock - product.Reserved
+		if qty > available {
+			shortItems = append(shortItems, model.OutOfStockItem{
+				ProductID: product.ID.String(),
+				Name:      product.Name,
+				Requested: qty,
+				Available: max(available, 0),
+			})
+		}
+	}
+	if len(shortItems) > 0 {
+		return nil, &utils.OutOfStockError{Items: shortItems}
+	}
 
+	for productID, qty := range requestedQty {
+		if err := o.ProductRepository.ReserveStock(tx, productID, qty); err != nil {
+			o.Log.Warnf("Failed reserve stock : %+v", err)
+			return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
 		}
+	}
 
-		// fallback kalau bukan *midtrans.Error
-		return fmt.Errorf("%w: midtrans error: %v", utils.ErrPayment, err)
+	lines := make([]service.PricingLine, len(orderItems))
+	for i, item := range orderItems {
+		lines[i] = service.PricingLine{
+			ID:    item.ProductID.String(),
+			Name:  item.ProductName,
+			Price: item.Price,
+			Qty:   item.Qty,
+		}
 	}
-	_, deeplink := utils.ExtractMidtransURLs(resp)
 
-	amountFloat, err := strconv.ParseFloat(resp.GrossAmount, 64)
+	// ✅ Voucher di-lock sampai order commit, jadi kuota tidak bisa terpakai lebih dari limit
+	var voucher *entity.Voucher
+	var discount int64
+	if request.VoucherCode != "" {
+		voucher, discount, err = o.applyVoucher(tx, request, guest, productMap, orderItems)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// ✅ Poin loyalty jadi diskon tambahan setelah voucher, akun poin di-lock sampai order commit
+	var loyaltyAccount *entity.LoyaltyAccount
+	var pointsDiscount int64
+	if request.RedeemPoints > 0 {
+		loyaltyAccount, pointsDiscount, err = o.applyPoints(tx, request, orderItems, discount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// ✅ Slot pickup di-lock sampai order commit, jadi kapasitas slot tidak bisa terlewati
+	fulfillment, err := o.resolveFulfillment(tx, request)
 	if err != nil {
-		o.Log.Warnf("Failed to parse GrossAmount: %v", err)
+		return nil, err
+	}
+	scheduledFor, err := o.resolveSchedule(request)
+	if err != nil {
+		return nil, err
 	}
-	amount := int64(amountFloat)
 
-	var expiredAt *time.Time
-	if resp.ExpiryTime != "" {
-		t, err := time.Parse("2006-01-02 15:04:05", resp.ExpiryTime)
-		if err == nil {
-			expiredAt = &t
+	// ✅ Ongkir hanya untuk order delivery, alamat wajib & harus masuk shipping zone
+	delivery := &deliveryResult{}
+	if fulfillment.Type == entity.FulfillmentDelivery {
+		delivery, err = o.resolveDelivery(tx, request, orderItems)
+		if err != nil {
+			return nil, err
 		}
 	}
+	pricing := o.Pricing.Calculate(lines, discount+pointsDiscount, delivery.Fee)
+	if pricing.Total <= 0 {
+		// Midtrans tidak bisa charge 0 rupiah
+		return nil, fmt.Errorf("%w: order total must be greater than zero", utils.ErrValidation)
+	}
+
+	var payment *paymentResult
+	switch {
+	case request.PaymentMethod == entity.PaymentMethodWallet:
+		// ✅ Saldo dipotong di transaksi yang sama dengan order, kalau order gagal dibuat saldo ikut rollback
+		payment, err = o.debitWallet(tx, request, invoiceNumber, pricing)
+	case checkoutMode == entity.CheckoutModeSnap:
+		payment, err = o.createSnap(request, invoiceNumber, pricing)
+	default:
+		payment, err = o.chargeCoreAPI(request, invoiceNumber, pricing)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// ✅ Transaksi Midtrans sudah terbentuk, kalau order gagal disimpan transaksinya dibatalkan supaya tidak yatim
+	committed := false
+	if request.PaymentMethod != entity.PaymentMethodWallet {
+		defer func() {
+			if !committed {
+				o.cancelOrphanPayment(invoiceNumber)
+			}
+		}()
+	}
 
-	// ✅ Buat entity order
+	// ✅ Buat entity order, sama untuk Core API & Snap
 	order := &entity.Order{
-		UserID:        utils.MustParseUUID(request.CustomerID),
-		InvoiceNumber: resp.OrderID,           // ex: INV-20250909-0003
-		Status:        resp.TransactionStatus, // pending / settlement / cancel
-		Amount:        amount,
-		PaymentMethod: request.PaymentMethod, // ex: "e-wallet"
-		PaymentType:   resp.PaymentType,      // ex: "gopay"
-		TransactionID: resp.TransactionID,
-		RedirectURL:   deeplink, // kalau pakai coreapi
-		ExpiredAt:     expiredAt,
-		OrderItems:    orderItems,
-		Notes:         request.Notes,           // simpan catatan order
-		ShippingAddr:  request.ShippingAddress, // simpan alamat pengiriman
+		UserID:          utils.MustParseUUID(request.CustomerID),
+		CustomerName:    request.CustomerName,
+		CustomerEmail:   request.CustomerEmail,
+		CustomerPhone:   request.CustomerPhone,
+		InvoiceNumber:   invoiceNumber, // ex: INV-20250909-0003
+		Status:          entity.OrderStatusPending,
+		Subtotal:        pricing.Subtotal,
+		DiscountAmount:  pricing.Discount,
+		ServiceCharge:   pricing.ServiceCharge,
+		TaxAmount:       pricing.Tax,
+		DeliveryFee:     pricing.DeliveryFee,
+		RoundingAmount:  pricing.Rounding,
+		TaxInclusive:    pricing.TaxInclusive,
+		TaxRate:         pricing.TaxRate,
+		ServiceRate:     pricing.ServiceRate,
+		Amount:          payment.Amount,
+		CheckoutMode:    checkoutMode,
+		PaymentMethod:   request.PaymentMethod, // ex: "e-wallet"
+		PaymentType:     payment.PaymentType,   // ex: "gopay", snap baru terisi dari notifikasi
+		TransactionID:   payment.TransactionID,
+		SnapToken:       payment.SnapToken,
+		RedirectURL:     payment.RedirectURL,
+		QRString:        payment.QRString,
+		QRURL:           payment.QRURL,
+		DeeplinkURL:     payment.DeeplinkURL,
+		VANumber:        payment.VANumber,
+		VABank:          payment.VABank,
+		ExpiredAt:       payment.ExpiredAt,
+		OrderItems:      orderItems,
+		Notes:           request.Notes,           // simpan catatan order
+		ShippingAddr:    request.ShippingAddress, // simpan alamat pengiriman
+		ShippingCity:    delivery.City,
+		ShippingPostal:  delivery.PostalCode,
+		ShippingZoneID:  delivery.ZoneID,
+		FulfillmentType: fulfillment.Type,
+		PickupSlotID:    fulfillment.SlotID,
+		PickupAt:        fulfillment.PickupAt,
+		ScheduledFor:    scheduledFor,
+		StockStatus:     entity.StockStatusReserved,
+	}
+	if voucher != nil {
+		order.VoucherID = &voucher.ID
+		order.VoucherCode = voucher.Code
+	}
+	if loyaltyAccount != nil {
+		order.PointsRedeemed = request.RedeemPoints
+		order.PointsDiscount = pointsDiscount
 	}
 
 	if err := o.OrderRepository.Create(tx, order); err != nil {
 		o.Log.Warnf("Failed create order to database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if voucher != nil {
+		// discount order sudah termasuk potongan poin, yang dicatat hanya potongan dari voucher
+		if err := o.redeemVoucher(tx, voucher, order, discount); err != nil {
+			o.Log.Warnf("Failed redeem voucher : %+v", err)
+			return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+		}
+	}
+	if loyaltyAccount != nil {
+		redemption := &entity.LoyaltyTransaction{
+			Type:    entity.LoyaltyTypeRedeem,
+			Points:  -order.PointsRedeemed,
+			OrderID: &order.ID,
+			Reason:  "redeemed for " + order.InvoiceNumber,
+			Actor:   "customer:" + request.CustomerID,
+		}
+		if err := postLoyaltyPoints(tx, o.LoyaltyRepository, loyaltyAccount, redemption); err != nil {
+			o.Log.Warnf("Failed redeem loyalty points : %+v", err)
+			return nil, err
+		}
+	}
+
+	history := &entity.OrderStatusHistory{
+		OrderID:  order.ID,
+		ToStatus: entity.OrderStatusPending,
+		Reason:   "order created",
+		Actor:    "customer:" + request.CustomerID,
+	}
+	if err := o.HistoryRepository.Create(tx, history); err != nil {
+		o.Log.Warnf("Failed create order history to database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	// charge bisa langsung settle / ditolak (ex: kartu), status ikut response Midtrans
+	actor, reason := entity.ActorMidtransCharge, "midtrans "+payment.TransactionStatus
+	if order.PaymentMethod == entity.PaymentMethodWallet {
+		actor, reason = history.Actor, "paid with wallet balance"
+	}
+	if status := utils.MapMidtransStatus(payment.TransactionStatus, payment.FraudStatus); status != order.Status {
+		if err := o.transition(tx, order, status, actor, reason); err != nil {
+			return nil, err
+		}
+		if err := o.OrderRepository.Update(tx, order); err != nil {
+			o.Log.Warnf("Failed update order to database : %+v", err)
+			return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		o.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	committed = true
+
+	o.Mail.SendOrder(notification.TemplateOrderPlaced, request.CustomerEmail, request.CustomerName, order, nil)
+	o.notifyStatus(ctx, order, entity.OrderStatusPending)
+
+	return converter.OrderToCreateResponse(order), nil
+}
+
+func (o *OrderUseCase) HandleNotification(ctx context.Context, request *model.MidtransNotificationRequest, rawBody []byte) error {
+	err := o.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(o.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	if !o.Payment.VerifySignature(request.OrderID, request.StatusCode, request.GrossAmount, request.SignatureKey) {
+		o.Log.Warnf("Invalid midtrans signature, order_id=%s", request.OrderID)
+		return utils.ErrInvalidSignature
+	}
+
+	tx := o.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	order, err := o.OrderRepository.FindByInvoiceNumberForUpdate(tx, request.OrderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			o.Log.Infof("order not found, invoice=%s", request.OrderID)
+			return utils.ErrNotFound
+		}
+		o.Log.Warnf("Failed find order from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	// Midtrans bisa kirim notifikasi yang sama berkali-kali (retry), cukup diproses sekali
+	duplicate, err := o.PaymentLogRepository.ExistsByTransactionStatus(tx, order.ID, request.TransactionID, request.TransactionStatus)
+	if err != nil {
+		o.Log.Warnf("Failed check payment log : %+v", err)
 		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
 	}
+	if duplicate {
+		o.Log.Infof("Duplicate midtrans notification, invoice=%s status=%s", order.InvoiceNumber, request.TransactionStatus)
+		return nil
+	}
+
+	payment := &providerStatus{
+		TransactionID:     request.TransactionID,
+		TransactionStatus: request.TransactionStatus,
+		FraudStatus:       request.FraudStatus,
+		PaymentType:       request.PaymentType,
+		RawResponse:       rawBody,
+	}
+	from := order.Status
+	status := utils.MapMidtransStatus(request.TransactionStatus, request.FraudStatus)
+	if err := o.applyProviderStatus(tx, order, payment, status, entity.ActorMidtransNotification); err != nil {
+		return err
+	}
 
 	if err := tx.Commit().Error; err != nil {
 		o.Log.Warnf("Failed commit transaction : %+v", err)
 		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
 	}
 
+	o.notifyStatus(ctx, order, from)
+
+	return nil
+}
+
+// paymentResult hasil pembuatan transaksi di payment provider, bentuknya sama untuk Core API & Snap
+type paymentResult struct {
+	TransactionID     string
+	TransactionStatus string
+	FraudStatus       string
+	PaymentType       string
+	Amount            int64
+	ExpiredAt         *time.Time
+	QRString          string
+	QRURL             string
+	DeeplinkURL       string
+	VANumber          string
+	VABank            string
+	SnapToken         string
+	RedirectURL       string
+}
+
+func (o *OrderUseCase) chargeCoreAPI(request *model.CreateOrderRequest, invoiceNumber string, pricing *service.PricingResult) (*paymentResult, error) {
+	chargeReq, err := utils.BuildChargeReq(request, invoiceNumber, pricing.Total, pricing.MidtransItems(), o.Payment.SupportedMethods())
+	if err != nil {
+		return nil, fmt.Errorf("build charge req failed: %w", err)
+	}
+
+	resp, err := o.Payment.Charge(chargeReq)
+	if err != nil {
+		return nil, o.paymentError(err)
+	}
+
+	amountFloat, err := strconv.ParseFloat(resp.GrossAmount, 64)
+	if err != nil {
+		o.Log.Warnf("Failed to parse GrossAmount: %v", err)
+	}
+
+	result := &paymentResult{
+		TransactionID:     resp.TransactionID,
+		TransactionStatus: resp.TransactionStatus,
+		FraudStatus:       resp.FraudStatus,
+		PaymentType:       resp.PaymentType,
+		Amount:            int64(amountFloat),
+		ExpiredAt:         utils.ParseMidtransTime(resp.ExpiryTime),
+		QRString:          resp.QRString,
+	}
+	result.QRURL, result.DeeplinkURL = utils.ExtractMidtransURLs(resp)
+	result.VABank, result.VANumber = utils.ExtractMidtransVA(resp)
+
+	return result, nil
+}
+
+// createSnap transaksi Snap belum punya transaction id & payment type, keduanya diisi dari notifikasi
+func (o *OrderUseCase) createSnap(request *model.CreateOrderRequest, invoiceNumber string, pricing *service.PricingResult) (*paymentResult, error) {
+	now := time.Now()
+	snapReq, err := utils.BuildSnapReq(request, invoiceNumber, pricing.Total, pricing.MidtransItems(), o.Payment.SupportedMethods(), now)
+	if err != nil {
+		return nil, fmt.Errorf("build snap req failed: %w", err)
+	}
+
+	resp, err := o.Payment.CreateSnap(snapReq)
+	if err != nil {
+		return nil, o.paymentError(err)
+	}
+
+	expiredAt := now.Add(utils.SnapExpiryDuration)
+	return &paymentResult{
+		TransactionStatus: "pending",
+		Amount:            pricing.Total,
+		ExpiredAt:         &expiredAt,
+		SnapToken:         resp.Token,
+		RedirectURL:       resp.RedirectURL,
+	}, nil
+}
+
+// applyPoints lock akun poin customer lalu hitung diskon dari poin yang di-redeem. Diskon poin tidak boleh
+// melebihi subtotal yang tersisa setelah diskon voucher.
+func (o *OrderUseCase) applyPoints(tx *gorm.DB, request *model.CreateOrderRequest, orderItems []entity.OrderItem, voucherDiscount int64) (*entity.LoyaltyAccount, int64, error) {
+	if request.RedeemPoints < o.Loyalty.MinRedeem {
+		return nil, 0, fmt.Errorf("%w: minimum %d points to redeem", utils.ErrValidation, o.Loyalty.MinRedeem)
+	}
+
+	account, err := o.LoyaltyRepository.FindOrCreateForUpdate(tx, utils.MustParseUUID(request.CustomerID))
+	if err != nil {
+		o.Log.Warnf("Failed find loyalty account from database : %+v", err)
+		return nil, 0, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if account.Balance < request.RedeemPoints {
+		return nil, 0, fmt.Errorf("%w: loyalty points balance %d, requested %d", utils.ErrInsufficientBalance, account.Balance, request.RedeemPoints)
+	}
+
+	var subtotal int64
+	for _, item := range orderItems {
+		subtotal += item.Subtotal
+	}
+	discount := o.Loyalty.Discount(request.RedeemPoints)
+	if remaining := subtotal - voucherDiscount; discount > remaining {
+		return nil, 0, fmt.Errorf("%w: points discount %d exceeds order subtotal %d, max %d points",
+			utils.ErrValidation, discount, remaining, remaining/o.Loyalty.PointValue)
+	}
+
+	return account, discount, nil
+}
+
+// debitWallet potong saldo customer sebesar total order, hasilnya diperlakukan seperti charge yang langsung settle
+func (o *OrderUseCase) debitWallet(tx *gorm.DB, request *model.CreateOrderRequest, invoiceNumber string, pricing *service.PricingResult) (*paymentResult, error) {
+	entry := &entity.WalletEntry{
+		Type:           entity.WalletEntryDebit,
+		Amount:         pricing.Total,
+		Source:         entity.WalletSourceOrder,
+		ReferenceID:    invoiceNumber,
+		CounterAccount: entity.WalletAccountSales,
+		Description:    "payment " + invoiceNumber,
+		Actor:          "customer:" + request.CustomerID,
+	}
+	if _, err := postWalletEntry(tx, o.WalletRepository, utils.MustParseUUID(request.CustomerID), entry); err != nil {
+		if !errors.Is(err, utils.ErrInsufficientBalance) {
+			o.Log.Warnf("Failed debit wallet : %+v", err)
+		}
+		return nil, err
+	}
+
+	return &paymentResult{
+		TransactionID:     entry.ID.String(),
+		TransactionStatus: "settlement",
+		PaymentType:       entity.PaymentMethodWallet,
+		Amount:            pricing.Total,
+	}, nil
+}
+
+// applyVoucher lock voucher lalu cek masa berlaku, kuota, restriksi produk & minimal belanja.
+// Diskon dihitung dari subtotal item yang masuk restriksi voucher saja.
+// Voucher dengan limit per customer butuh login, customer_id dari body guest bisa diganti-ganti.
+func (o *OrderUseCase) applyVoucher(tx *gorm.DB, request *model.CreateOrderRequest, guest bool, productMap map[uuid.UUID]entity.Product,
+	orderItems []entity.OrderItem) (*entity.Voucher, int64, error) {
+	voucher, err := o.VoucherRepository.FindByCodeForUpdate(tx, strings.ToUpper(request.VoucherCode))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, fmt.Errorf("%w: voucher %s not found", utils.ErrValidation, request.VoucherCode)
+		}
+		o.Log.Warnf("Failed find voucher from database : %+v", err)
+		return nil, 0, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if !voucher.IsUsable(time.Now()) {
+		return nil, 0, fmt.Errorf("%w: voucher %s is not active or has expired", utils.ErrValidation, voucher.Code)
+	}
+	if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+		return nil, 0, fmt.Errorf("%w: voucher %s usage limit reached", utils.ErrConflict, voucher.Code)
+	}
+	if voucher.PerCustomerLimit > 0 && guest {
+		return nil, 0, fmt.Errorf("%w: login is required to use voucher %s", utils.ErrUnauthorized, voucher.Code)
+	}
+	if voucher.PerCustomerLimit > 0 {
+		used, err := o.VoucherRepository.CountCustomerRedemptions(tx, voucher.ID, utils.MustParseUUID(request.CustomerID))
+		if err != nil {
+			o.Log.Warnf("Failed count voucher redemptions : %+v", err)
+			return nil, 0, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+		}
+		if used >= int64(voucher.PerCustomerLimit) {
+			return nil, 0, fmt.Errorf("%w: voucher %s usage limit per customer reached", utils.ErrConflict, voucher.Code)
+		}
+	}
+
+	var eligible int64
+	for _, item := range orderItems {
+		product := productMap[item.ProductID]
+		if voucher.Applies(&product) {
+			eligible += item.Subtotal
+		}
+	}
+	if eligible == 0 {
+		return nil, 0, fmt.Errorf("%w: voucher %s is not applicable to the ordered items", utils.ErrValidation, voucher.Code)
+	}
+	if eligible < voucher.MinSpend {
+		return nil, 0, fmt.Errorf("%w: voucher %s requires minimum spend of %d", utils.ErrValidation, voucher.Code, voucher.MinSpend)
+	}
+
+	return voucher, voucher.DiscountFor(eligible), nil
+}
+
+// fulfillmentResult cara order diterima customer, slot & waktu ambil hanya untuk pickup
+type fulfillmentResult struct {
+	Type     string
+	SlotID   *uuid.UUID
+	PickupAt *time.Time
+}
+
+// resolveFulfillment validasi kombinasi fulfillment_type dengan alamat / slot, lalu reservasi slot pickup.
+// Slot dihitung penuh kalau jumlah order aktif di slot & tanggal yang sama sudah mencapai Capacity.
+// Pre-order (scheduled_for) hanya untuk pickup dan tidak memakai slot.
+func (o *OrderUseCase) resolveFulfillment(tx *gorm.DB, request *model.CreateOrderRequest) (*fulfillmentResult, error) {
+	fulfillmentType := request.FulfillmentType
+	if fulfillmentType == "" {
+		fulfillmentType = entity.FulfillmentDelivery
+	}
+
+	if fulfillmentType != entity.FulfillmentDelivery && request.ShippingAddress != "" {
+		return nil, fmt.Errorf("%w: shipping_address is only allowed for delivery", utils.ErrValidation)
+	}
+	if fulfillmentType != entity.FulfillmentPickup {
+		if request.PickupSlotID != "" || request.PickupDate != "" {
+			return nil, fmt.Errorf("%w: pickup_slot_id is only allowed for pickup", utils.ErrValidation)
+		}
+		if request.ScheduledFor != "" {
+			return nil, fmt.Errorf("%w: scheduled_for is only allowed for pickup", utils.ErrValidation)
+		}
+		return &fulfillmentResult{Type: fulfillmentType}, nil
+	}
+	if request.ScheduledFor != "" {
+		if request.PickupSlotID != "" {
+			return nil, fmt.Errorf("%w: use either pickup_slot_id or scheduled_for", utils.ErrValidation)
+		}
+		return &fulfillmentResult{Type: fulfillmentType}, nil
+	}
+	if request.PickupSlotID == "" {
+		return nil, fmt.Errorf("%w: pickup_slot_id or scheduled_for is required for pickup", utils.ErrValidation)
+	}
+
+	now := time.Now()
+	day, err := pickupDay(request.PickupDate, now, o.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	slot, err := o.PickupSlot.FindByIDForUpdate(tx, utils.MustParseUUID(request.PickupSlotID))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: pickup slot not found", utils.ErrValidation)
+		}
+		o.Log.Warnf("Failed find pickup slot from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if !slot.IsActive {
+		return nil, fmt.Errorf("%w: pickup slot %s is not available", utils.ErrValidation, slot.Name)
+	}
+	if !slot.EndOn(day).After(now) {
+		return nil, fmt.Errorf("%w: pickup slot %s has already passed", utils.ErrValidation, slot.Name)
+	}
+
+	pickupAt := slot.StartOn(day)
+	booked, err := o.OrderRepository.CountPickupBookings(tx, slot.ID, pickupAt)
+	if err != nil {
+		o.Log.Warnf("Failed count pickup bookings from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if booked >= int64(slot.Capacity) {
+		return nil, fmt.Errorf("%w: pickup slot %s is full", utils.ErrConflict, slot.Name)
+	}
+
+	return &fulfillmentResult{
+		Type:     fulfillmentType,
+		SlotID:   &slot.ID,
+		PickupAt: &pickupAt,
+	}, nil
+}
+
+// resolveSchedule waktu pre-order dari request, nil kalau order biasa. Dicek terhadap jam buka toko & lead time.
+func (o *OrderUseCase) resolveSchedule(request *model.CreateOrderRequest) (*time.Time, error) {
+	if request.ScheduledFor == "" {
+		return nil, nil
+	}
+
+	scheduledFor, err := time.Parse(time.RFC3339, request.ScheduledFor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid scheduled_for %s", utils.ErrValidation, request.ScheduledFor)
+	}
+	if err := o.Schedule.Check(scheduledFor, time.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+	return &scheduledFor, nil
+}
+
+// deliveryResult zone & ongkir order delivery, kosong untuk order pickup / dine-in
+type deliveryResult struct {
+	ZoneID     *uuid.UUID
+	City       string
+	PostalCode string
+	Fee        int64
+}
+
+// resolveDelivery cari shipping zone paling spesifik untuk kota / kode pos tujuan. Kota & kode pos diambil dari request,
+// kalau kosong pakai profil customer. Alamat di luar semua zone ditolak.
+func (o *OrderUseCase) resolveDelivery(tx *gorm.DB, request *model.CreateOrderRequest, orderItems []entity.OrderItem) (*deliveryResult, error) {
+	if strings.TrimSpace(request.ShippingAddress) == "" {
+		return nil, fmt.Errorf("%w: shipping_address is required for delivery", utils.ErrValidation)
+	}
+
+	city, postalCode := request.ShippingCity, request.ShippingPostal
+	if city == "" && postalCode == "" {
+		customer, err := o.CustomerRepository.FindById(tx, &entity.Customer{}, request.CustomerID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: customer not found", utils.ErrValidation)
+			}
+			o.Log.Warnf("Failed find customer from database : %+v", err)
+			return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+		}
+		city, postalCode = customer.City, customer.PostalCode
+	}
+	if city == "" && postalCode == "" {
+		return nil, fmt.Errorf("%w: shipping_city or shipping_postal_code is required for delivery", utils.ErrValidation)
+	}
+
+	zones, err := o.ShippingZone.FindActive(tx)
+	if err != nil {
+		o.Log.Warnf("Failed find shipping zones from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	var zone *entity.ShippingZone
+	for i := range zones {
+		if zones[i].Matches(city, postalCode) && (zone == nil || zones[i].Specificity() > zone.Specificity()) {
+			zone = &zones[i]
+		}
+	}
+	if zone == nil {
+		return nil, fmt.Errorf("%w: shipping address is outside delivery area", utils.ErrValidation)
+	}
+
+	var subtotal int64
+	for _, item := range orderItems {
+		subtotal += item.Subtotal
+	}
+	if subtotal < zone.MinOrder {
+		return nil, fmt.Errorf("%w: minimum order for delivery to %s is %d", utils.ErrValidation, zone.Name, zone.MinOrder)
+	}
+
+	return &deliveryResult{
+		ZoneID:     &zone.ID,
+		City:       city,
+		PostalCode: postalCode,
+		Fee:        zone.FeeFor(subtotal),
+	}, nil
+}
+
+// redeemVoucher catat pemakaian voucher di transaksi yang sama dengan order
+func (o *OrderUseCase) redeemVoucher(tx *gorm.DB, voucher *entity.Voucher, order *entity.Order, amount int64) error {
+	redemption := &entity.VoucherRedemption{
+		VoucherID:  voucher.ID,
+		OrderID:    order.ID,
+		CustomerID: order.UserID,
+		Code:       voucher.Code,
+		Amount:     amount,
+		Status:     entity.RedemptionStatusApplied,
+	}
+	if err := o.VoucherRepository.CreateRedemption(tx, redemption); err != nil {
+		return err
+	}
+	return o.VoucherRepository.AddUsage(tx, voucher.ID, 1)
+}
+
+// releaseVoucher kembalikan kuota voucher kalau order tidak jadi dibayar
+func (o *OrderUseCase) releaseVoucher(tx *gorm.DB, order *entity.Order) error {
+	if order.VoucherID == nil {
+		return nil
+	}
+	switch order.Status {
+	case entity.OrderStatusFailed, entity.OrderStatusExpired, entity.OrderStatusCancelled:
+	default:
+		return nil
+	}
+
+	redemption, err := o.VoucherRepository.FindAppliedRedemption(tx, order.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	redemption.Status = entity.RedemptionStatusReleased
+	if err := o.VoucherRepository.UpdateRedemption(tx, redemption); err != nil {
+		return err
+	}
+	return o.VoucherRepository.AddUsage(tx, redemption.VoucherID, -1)
+}
+
+// nextInvoiceNumber ambil nomor urut harian di luar transaksi order, jadi row counter tidak ke-lock
+// selama charge ke Midtrans. Nomor yang sudah diambil tidak dipakai ulang walaupun order gagal.
+func (o *OrderUseCase) nextInvoiceNumber(ctx context.Context) (string, error) {
+	now := time.Now()
+	sequence, err := o.InvoiceCounter.Next(o.DB.WithContext(ctx), o.InvoiceFormat, now, &entity.Order{})
+	if err != nil {
+		return "", err
+	}
+	return o.InvoiceFormat.Generate(now, sequence), nil
+}
+
+// cancelOrphanPayment batalkan transaksi Midtrans dari order yang gagal tersimpan.
+// Snap yang belum dibayar belum punya transaksi di Midtrans, token-nya juga tidak pernah sampai ke customer.
+func (o *OrderUseCase) cancelOrphanPayment(invoiceNumber string) {
+	if _, err := o.Payment.Cancel(invoiceNumber); err != nil {
+		if errors.Is(err, utils.ErrPaymentNotFound) {
+			return
+		}
+		o.Log.Errorf("Failed cancel orphan payment, invoice=%s : %+v", invoiceNumber, err)
+		return
+	}
+	o.Log.Infof("Cancelled payment of order that failed to save, invoice=%s", invoiceNumber)
+}
+
+// paymentError bungkus error dari payment provider jadi ErrPayment
+func (o *OrderUseCase) paymentError(err error) error {
+	// cek apakah error dari midtrans
+	if midErr, ok := err.(*midtrans.Error); ok && midErr != nil {
+		o.Log.Errorf("Midtrans error: StatusCode=%v, Message=%v", midErr.StatusCode, midErr.Message)
+		return fmt.Errorf("%w: midtrans error: %s", utils.ErrPayment, midErr.Message)
+	}
+	if errors.Is(err, utils.ErrPayment) {
+		return err
+	}
+
+	// fallback kalau bukan *midtrans.Error
+	return fmt.Errorf("%w: midtrans error: %v", utils.ErrPayment, err)
+}
+
+// providerStatus status transaksi dari payment provider, sumbernya bisa notifikasi, cek status, atau rekonsiliasi
+type providerStatus struct {
+	TransactionID     string
+	TransactionStatus string
+	FraudStatus       string
+	PaymentType       string
+	RawResponse       []byte
+}
+
+// applyProviderStatus catat payment log lalu pindahkan order ke status tujuan. Dipakai notifikasi, expiry worker
+// & rekonsiliasi supaya aturannya sama: transisi yang tidak valid cukup tercatat di payment log, status tidak ditimpa.
+func (o *OrderUseCase) applyProviderStatus(tx *gorm.DB, order *entity.Order, payment *providerStatus, status, actor string) error {
+	transactionID := payment.TransactionID
+	if transactionID == "" {
+		transactionID = order.TransactionID
+	}
+
+	paymentLog := &entity.PaymentLog{
+		OrderID:             order.ID,
+		MidtransTransaction: transactionID,
+		Status:              payment.TransactionStatus,
+		RawResponse:         datatypes.JSON(payment.RawResponse),
+	}
+	if err := o.PaymentLogRepository.Create(tx, paymentLog); err != nil {
+		o.Log.Warnf("Failed create payment log to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if status != order.Status {
+		err := o.transition(tx, order, status, actor, "midtrans "+payment.TransactionStatus)
+		if err != nil {
+			if !errors.Is(err, utils.ErrInvalidStatusTransition) {
+				return err
+			}
+			o.Log.Warnf("Ignore midtrans status %s for invoice=%s : %v", payment.TransactionStatus, order.InvoiceNumber, err)
+		}
+	}
+
+	order.TransactionID = transactionID
+	if payment.PaymentType != "" {
+		order.PaymentType = payment.PaymentType
+	}
+
+	if err := o.OrderRepository.Update(tx, order); err != nil {
+		o.Log.Warnf("Failed update order to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+// transition pindahkan status order sesuai state machine di entity. Setiap perubahan dicatat di history,
+// stok, kuota voucher & poin loyalty ikut disesuaikan, order sendiri tetap disimpan oleh caller.
+func (o *OrderUseCase) transition(tx *gorm.DB, order *entity.Order, to, actor, reason string) error {
+	if !order.CanTransitionTo(to) {
+		return fmt.Errorf("%w: %s to %s", utils.ErrInvalidStatusTransition, order.Status, to)
+	}
+
+	history := &entity.OrderStatusHistory{
+		OrderID:    order.ID,
+		FromStatus: order.Status,
+		ToStatus:   to,
+		Reason:     reason,
+		Actor:      actor,
+	}
+	if err := o.HistoryRepository.Create(tx, history); err != nil {
+		o.Log.Warnf("Failed create order history to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	order.Status = to
+	if err := o.syncStock(tx, order); err != nil {
+		o.Log.Warnf("Failed sync stock : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if err := o.releaseVoucher(tx, order); err != nil {
+		o.Log.Warnf("Failed release voucher : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if err := o.syncPoints(tx, order, actor); err != nil {
+		o.Log.Warnf("Failed sync loyalty points : %+v", err)
+		return err
+	}
+	if to == entity.OrderStatusPaid {
+		o.releaseIfDue(order, time.Now())
+	}
+
+	return nil
+}
+
+// releaseIfDue tampilkan pre-order di antrian barista kalau sudah masuk prep window, order biasa selalu tampil
+func (o *OrderUseCase) releaseIfDue(order *entity.Order, now time.Time) bool {
+	if order.ScheduledFor == nil || order.KitchenReleasedAt != nil {
+		return false
+	}
+	if now.Before(o.Schedule.ReleaseAt(*order.ScheduledFor)) {
+		return false
+	}
+	order.KitchenReleasedAt = &now
+	return true
+}
+
+// syncStock commit stok yang di-hold kalau order paid, atau lepas hold kalau order gagal / expired
+func (o *OrderUseCase) syncStock(tx *gorm.DB, order *entity.Order) error {
+	if order.StockStatus != entity.StockStatusReserved {
+		return nil
+	}
+
+	var apply func(db *gorm.DB, id uuid.UUID, qty int) error
+	var next string
+	switch order.Status {
+	case entity.OrderStatusPaid:
+		apply, next = o.ProductRepository.CommitStock, entity.StockStatusCommitted
+	case entity.OrderStatusFailed, entity.OrderStatusExpired, entity.OrderStatusCancelled:
+		apply, next = o.ProductRepository.ReleaseStock, entity.StockStatusReleased
+	default:
+		return nil
+	}
+
+	items, err := o.OrderRepository.FindItemsByOrderID(tx, order.ID)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := apply(tx, item.ProductID, item.Qty); err != nil {
+			return err
+		}
+	}
+
+	order.StockStatus = next
+	return nil
+}
+
+// syncPoints kasih poin saat order paid, atau kembalikan poin yang di-redeem kalau order gagal / expired / cancelled
+func (o *OrderUseCase) syncPoints(tx *gorm.DB, order *entity.Order, actor string) error {
+	switch order.Status {
+	case entity.OrderStatusPaid:
+		return o.earnPoints(tx, order, actor)
+	case entity.OrderStatusFailed, entity.OrderStatusExpired, entity.OrderStatusCancelled:
+		return o.restorePoints(tx, order, order.PointsRedeemed-order.PointsRestored, actor)
+	default:
+		return nil
+	}
+}
+
+// earnPoints poin dihitung dari nominal item setelah diskon (tanpa pajak, service & ongkir), dikali multiplier kategori
+func (o *OrderUseCase) earnPoints(tx *gorm.DB, order *entity.Order, actor string) error {
+	if o.Loyalty.EarnUnit <= 0 || order.PointsEarned > 0 || order.Subtotal <= 0 {
+		return nil
+	}
+
+	// order guest dengan customer_id yang tidak terdaftar tidak dapat poin
+	if _, err := o.CustomerRepository.FindById(tx, &entity.Customer{}, order.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	items, err := o.OrderRepository.FindItemsByOrderID(tx, order.ID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	productIDs := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		productIDs[i] = item.ProductID
+	}
+	products, err := o.ProductRepository.FindByIDs(tx.Preload("Category"), productIDs)
+	if err != nil {
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	categories := make(map[uuid.UUID]string, len(products))
+	for _, product := range products {
+		categories[product.ID] = product.Category.Slug
+	}
+
+	lines := make([]service.LoyaltyLine, len(items))
+	for i, item := range items {
+		lines[i] = service.LoyaltyLine{
+			CategorySlug: categories[item.ProductID],
+			Amount:       item.Subtotal * (order.Subtotal - order.DiscountAmount) / order.Subtotal,
+		}
+	}
+	points := o.Loyalty.Earn(lines)
+	if points <= 0 {
+		return nil
+	}
+
+	account, err := o.LoyaltyRepository.FindOrCreateForUpdate(tx, order.UserID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	transaction := &entity.LoyaltyTransaction{
+		Type:      entity.LoyaltyTypeEarn,
+		Points:    points,
+		ExpiresAt: o.Loyalty.ExpiresAt(time.Now()),
+		OrderID:   &order.ID,
+		Reason:    "earned from " + order.InvoiceNumber,
+		Actor:     actor,
+	}
+	if err := postLoyaltyPoints(tx, o.LoyaltyRepository, account, transaction); err != nil {
+		return err
+	}
+
+	order.PointsEarned = points
+	return nil
+}
+
+// restorePoints kembalikan poin yang di-redeem order ini, jadi lot baru dengan tanggal expire baru
+func (o *OrderUseCase) restorePoints(tx *gorm.DB, order *entity.Order, points int64, actor string) error {
+	if points <= 0 {
+		return nil
+	}
+
+	account, err := o.LoyaltyRepository.FindOrCreateForUpdate(tx, order.UserID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	transaction := &entity.LoyaltyTransaction{
+		Type:      entity.LoyaltyTypeRestore,
+		Points:    points,
+		ExpiresAt: o.Loyalty.ExpiresAt(time.Now()),
+		OrderID:   &order.ID,
+		Reason:    "restored from " + order.InvoiceNumber,
+		Actor:     actor,
+	}
+	if err := postLoyaltyPoints(tx, o.LoyaltyRepository, account, transaction); err != nil {
+		return err
+	}
+
+	order.PointsRestored += points
+	return nil
+}
+
+// refundPoints tarik poin earn & kembalikan poin redeem sesuai proporsi nominal refund. Refund terakhir ambil
+// seluruh sisanya supaya tidak ada selisih pembulatan. Poin earn yang sudah terpakai hanya ditarik sebatas saldo.
+func (o *OrderUseCase) refundPoints(tx *gorm.DB, order *entity.Order, amount int64, actor string) error {
+	if order.Amount <= 0 {
+		return nil
+	}
+
+	fullyRefunded := order.RefundedAmount+amount == order.Amount
+	restore := order.PointsRedeemed * amount / order.Amount
+	reverse := order.PointsEarned * amount / order.Amount
+	if fullyRefunded {
+		restore = order.PointsRedeemed - order.PointsRestored
+		reverse = order.PointsEarned - order.PointsReversed
+	}
+
+	if err := o.restorePoints(tx, order, restore, actor); err != nil {
+		return err
+	}
+	if reverse <= 0 {
+		return nil
+	}
+
+	account, err := o.LoyaltyRepository.FindOrCreateForUpdate(tx, order.UserID)
+	if err != nil {
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	order.PointsReversed += reverse
+	if reverse > account.Balance {
+		o.Log.Warnf("Loyalty points of %s already spent, reverse %d of %d point(s)", order.InvoiceNumber, account.Balance, reverse)
+		reverse = account.Balance
+	}
+	if reverse == 0 {
+		return nil
+	}
+
+	transaction := &entity.LoyaltyTransaction{
+		Type:    entity.LoyaltyTypeReverse,
+		Points:  -reverse,
+		OrderID: &order.ID,
+		Reason:  "refund " + order.InvoiceNumber,
+		Actor:   actor,
+	}
+	return postLoyaltyPoints(tx, o.LoyaltyRepository, account, transaction)
+}
+
+// ExpirePendingOrders cek order pending yang sudah lewat ExpiredAt ke Midtrans, lalu pindahkan ke status sebenarnya
+func (o *OrderUseCase) ExpirePendingOrders(ctx context.Context, limit int) (int, error) {
+	orders, err := o.OrderRepository.FindExpiredPending(o.DB.WithContext(ctx), time.Now(), limit)
+	if err != nil {
+		o.Log.Warnf("Failed find expired pending orders : %+v", err)
+		return 0, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	moved := 0
+	for _, order := range orders {
+		if err := o.expireOrder(ctx, order.ID, order.InvoiceNumber); err != nil {
+			o.Log.Warnf("Failed expire order, invoice=%s : %+v", order.InvoiceNumber, err)
+			continue
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
+// ReleaseScheduledOrders rilis pre-order yang sudah dibayar ke antrian barista saat masuk prep window, dipanggil worker
+func (o *OrderUseCase) ReleaseScheduledOrders(ctx context.Context, limit int) (int, error) {
+	now := time.Now()
+	orders, err := o.OrderRepository.FindScheduledToRelease(o.DB.WithContext(ctx), now.Add(o.Schedule.PrepWindow), limit)
+	if err != nil {
+		o.Log.Warnf("Failed find scheduled orders to release : %+v", err)
+		return 0, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	released := 0
+	for _, order := range orders {
+		ok, err := o.releaseOrder(ctx, order.ID, now)
+		if err != nil {
+			o.Log.Warnf("Failed release scheduled order, invoice=%s : %+v", order.InvoiceNumber, err)
+			continue
+		}
+		if ok {
+			released++
+		}
+	}
+
+	return released, nil
+}
+
+// releaseOrder kunci order lalu tandai sudah dirilis, event dikirim supaya layar barista menampilkan order
+func (o *OrderUseCase) releaseOrder(ctx context.Context, orderID uuid.UUID, now time.Time) (bool, error) {
+	tx := o.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	order, err := o.OrderRepository.FindByIDForUpdate(tx, orderID)
+	if err != nil {
+		return false, err
+	}
+	if order.Status != entity.OrderStatusPaid || !o.releaseIfDue(order, now) {
+		return false, nil
+	}
+
+	if err := o.OrderRepository.Update(tx, order); err != nil {
+		return false, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	o.publishOrder(ctx, order, order.Status)
+	return true, nil
+}
+
+func (o *OrderUseCase) expireOrder(ctx context.Context, orderID uuid.UUID, invoiceNumber string) error {
+	payment := &providerStatus{}
+
+	resp, err := o.Payment.Status(invoiceNumber)
+	switch {
+	case err == nil:
+		payment.TransactionID = resp.TransactionID
+		payment.TransactionStatus = resp.TransactionStatus
+		payment.FraudStatus = resp.FraudStatus
+		payment.RawResponse, _ = json.Marshal(resp)
+	case errors.Is(err, utils.ErrPaymentNotFound):
+		// transaksi tidak pernah tercatat di Midtrans, anggap expire
+		payment.TransactionStatus = "expire"
+		payment.RawResponse, _ = json.Marshal(map[string]string{
+			"source":         "expiry_worker",
+			"status_message": err.Error(),
+		})
+	default:
+		return fmt.Errorf("%w: %v", utils.ErrIntegration, err)
+	}
+
+	status := utils.MapMidtransStatus(payment.TransactionStatus, payment.FraudStatus)
+	if status == entity.OrderStatusPending {
+		// sudah lewat waktu bayar tapi di Midtrans masih pending
+		status = entity.OrderStatusExpired
+	}
+
+	moved, err := o.syncPendingOrder(ctx, orderID, payment, status, entity.ActorExpiryWorker)
+	if err != nil {
+		return err
+	}
+	if moved {
+		o.Log.Infof("Order %s moved to %s by expiry worker", invoiceNumber, status)
+	}
+	return nil
+}
+
+// ReconcilePendingOrder samakan order yang masih pending dengan status transaksi terbaru dari provider
+func (o *OrderUseCase) ReconcilePendingOrder(ctx context.Context, orderID uuid.UUID, resp *coreapi.TransactionStatusResponse, actor string) (bool, error) {
+	rawResponse, _ := json.Marshal(resp)
+	payment := &providerStatus{
+		TransactionID:     resp.TransactionID,
+		TransactionStatus: resp.TransactionStatus,
+		FraudStatus:       resp.FraudStatus,
+		PaymentType:       resp.PaymentType,
+		RawResponse:       rawResponse,
+	}
+	return o.syncPendingOrder(ctx, orderID, payment, utils.MapMidtransStatus(resp.TransactionStatus, resp.FraudStatus), actor)
+}
+
+// syncPendingOrder kunci order lalu terapkan status dari provider, hanya kalau order masih pending.
+// Return false kalau order sudah berubah duluan (ex: keburu diupdate notifikasi Midtrans).
+func (o *OrderUseCase) syncPendingOrder(ctx context.Context, orderID uuid.UUID, payment *providerStatus, status, actor string) (bool, error) {
+	tx := o.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	order, err := o.OrderRepository.FindByIDForUpdate(tx, orderID)
+	if err != nil {
+		return false, err
+	}
+	if order.Status != entity.OrderStatusPending {
+		return false, nil
+	}
+
+	if err := o.applyProviderStatus(tx, order, payment, status, actor); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	o.notifyStatus(ctx, order, entity.OrderStatusPending)
+	return order.Status != entity.OrderStatusPending, nil
+}
+
+func (o *OrderUseCase) FindAll(ctx context.Context, pagination *utils.PaginationRequest, filter *model.SearchOrderRequest) ([]model.OrderResponse, *utils.PaginationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := o.Validator.Validate.Struct(filter)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(o.Validator.Translator))
+			}
+			return nil, nil, fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	var orders []entity.Order
+
+	total, err := o.OrderRepository.FindAllWithFilter(o.DB.WithContext(ctx), &orders, pagination, filter, o.Location)
+	if err != nil {
+		o.Log.Warnf("Failed find all order from database : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	responses := make([]model.OrderResponse, len(orders))
+	for i, order := range orders {
+		responses[i] = *converter.OrderToResponse(&order)
+	}
+
+	totalPage := int((total + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginationRes := &utils.PaginationResponse{
+		Page:      pagination.Page,
+		Limit:     pagination.Limit,
+		OrderBy:   pagination.OrderBy,
+		SortBy:    pagination.SortBy,
+		Search:    pagination.Search,
+		TotalData: total,
+		TotalPage: totalPage,
+	}
+
+	return responses, paginationRes, nil
+}
+
+// FindScheduled pre-order per hari (timezone toko), default 7 hari mulai hari ini
+func (o *OrderUseCase) FindScheduled(ctx context.Context, request *model.SearchScheduledOrderRequest) ([]model.ScheduledOrderDayResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := o.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(o.Validator.Translator))
+			}
+			return nil, fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return nil, fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	now := time.Now().In(o.Location)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, o.Location)
+	if request.DateFrom != "" {
+		from, _ = time.ParseInLocation("2006-01-02", request.DateFrom, o.Location)
+	}
+	to := from.AddDate(0, 0, scheduledDefaultDays-1)
+	if request.DateTo != "" {
+		to, _ = time.ParseInLocation("2006-01-02", request.DateTo, o.Location)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: date_to must not be before date_from", utils.ErrValidation)
+	}
+	if to.After(from.AddDate(0, 0, scheduledMaxDays-1)) {
+		return nil, fmt.Errorf("%w: date range must not exceed %d days", utils.ErrValidation, scheduledMaxDays)
+	}
+
+	orders, err := o.OrderRepository.FindScheduledBetween(o.DB.WithContext(ctx), from, to.AddDate(0, 0, 1))
+	if err != nil {
+		o.Log.Warnf("Failed find scheduled orders from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	days := make([]model.ScheduledOrderDayResponse, 0)
+	index := make(map[string]int)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		index[date] = len(days)
+		days = append(days, model.ScheduledOrderDayResponse{Date: date, Orders: []model.OrderResponse{}})
+	}
+	for _, order := range orders {
+		i := index[order.ScheduledFor.In(o.Location).Format("2006-01-02")]
+		days[i].Orders = append(days[i].Orders, *converter.OrderToResponse(&order))
+		days[i].Total++
+	}
+
+	return days, nil
+}
+
+func (o *OrderUseCase) FindByID(ctx context.Context, orderID string) (*model.OrderResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, utils.ErrNotFound
+	}
+
+	order, err := o.OrderRepository.FindDetailByID(o.DB.WithContext(ctx), orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			o.Log.Infof("order not found, id=%s", orderID)
+			return nil, utils.ErrNotFound
+		}
+		o.Log.Warnf("Failed find order from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return converter.OrderToResponse(order), nil
+}
+
+// UpdateStatus ubah status order manual dari CMS, alasan & user yang mengubah disimpan di history
+func (o *OrderUseCase) UpdateStatus(ctx context.Context, orderID, userID string, request *model.UpdateOrderStatusRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := o.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(o.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	id, err := uuid.Parse(orderID)
+	if err != nil {
+		return utils.ErrNotFound
+	}
+
+	tx := o.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	order, err := o.OrderRepository.FindByIDForUpdate(tx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			o.Log.Infof("order not found, id=%s", orderID)
+			return utils.ErrNotFound
+		}
+		o.Log.Warnf("Failed find order from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if order.Status == request.Status {
+		return fmt.Errorf("%w: order status is already %s", utils.ErrConflict, request.Status)
+	}
+
+	from := order.Status
+	if err := o.transition(tx, order, request.Status, "cms:"+userID, request.Reason); err != nil {
+		return err
+	}
+
+	if err := o.OrderRepository.Update(tx, order); err != nil {
+		o.Log.Warnf("Failed update order to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		o.Log.Warnf("Failed commit transaction : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	o.notifyStatus(ctx, order, from)
+
+	return nil
+}
+
+// FindAllForCustomer list order milik customer yang login, filter customer selalu dari token
+func (o *OrderUseCase) FindAllForCustomer(ctx context.Context, customerID string, pagination *utils.PaginationRequest, filter *model.SearchOrderRequest) ([]model.OrderResponse, *utils.PaginationResponse, error) {
+	if _, err := uuid.Parse(customerID); err != nil {
+		return nil, nil, utils.ErrUnauthorized
+	}
+
+	filter.CustomerID = customerID
+	responses, paginationRes, err := o.FindAll(ctx, pagination, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range responses {
+		responses[i].StockStatus = ""
+	}
+
+	return responses, paginationRes, nil
+}
+
+func (o *OrderUseCase) FindByIDForCustomer(ctx context.Context, customerID, orderID string) (*model.OrderResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(customerID); err != nil {
+		return nil, utils.ErrUnauthorized
+	}
+	if _, err := uuid.Parse(orderID); err != nil {
+		return nil, utils.ErrNotFound
+	}
+
+	// order milik customer lain dianggap tidak ada
+	order, err := o.OrderRepository.FindDetailByIDAndUserID(o.DB.WithContext(ctx), orderID, customerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			o.Log.Infof("order not found, id=%s customer=%s", orderID, customerID)
+			return nil, utils.ErrNotFound
+		}
+		o.Log.Warnf("Failed find order from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return converter.OrderToCustomerResponse(order), nil
+}
+
+// Cancel batalkan order yang belum dibayar, transaksi di Midtrans ikut dibatalkan & hold stok dilepas
+func (o *OrderUseCase) Cancel(ctx context.Context, orderID, userID string, request *model.CancelOrderRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	err := o.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(o.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	id, err := uuid.Parse(orderID)
+	if err != nil {
+		return utils.ErrNotFound
+	}
+
+	tx := o.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	order, err := o.OrderRepository.FindByIDForUpdate(tx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			o.Log.Infof("order not found, id=%s", orderID)
+			return utils.ErrNotFound
+		}
+		o.Log.Warnf("Failed find order from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if order.Status == entity.OrderStatusCancelled {
+		return fmt.Errorf("%w: order %s is already cancelled", utils.ErrPaymentCancelled, order.InvoiceNumber)
+	}
+	if !order.CanTransitionTo(entity.OrderStatusCancelled) {
+		return fmt.Errorf("%w: order with status %s cannot be cancelled", utils.ErrInvalidStatusTransition, order.Status)
+	}
+
+	transactionID := order.TransactionID
+	transactionStatus := "cancel"
+	var rawResponse []byte
+
+	resp, err := o.Payment.Cancel(order.InvoiceNumber)
+	switch {
+	case err == nil:
+		transactionStatus = resp.TransactionStatus
+		if resp.TransactionID != "" {
+			transactionID = resp.TransactionID
+		}
+		rawResponse, _ = json.Marshal(resp)
+	case errors.Is(err, utils.ErrPaymentNotFound):
+		// transaksi tidak pernah tercatat di Midtrans, cukup dibatalkan di sisi kita
+		rawResponse, _ = json.Marshal(map[string]string{
+			"source":         "cms",
+			"status_message": err.Error(),
+		})
+	default:
+		return o.paymentError(err)
+	}
+
+	paymentLog := &entity.PaymentLog{
+		OrderID:             order.ID,
+		MidtransTransaction: transactionID,
+		Status:              transactionStatus,
+		RawResponse:         datatypes.JSON(rawResponse),
+	}
+	if err := o.PaymentLogRepository.Create(tx, paymentLog); err != nil {
+		o.Log.Warnf("Failed create payment log to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	from := order.Status
+	if err := o.transition(tx, order, entity.OrderStatusCancelled, "cms:"+userID, request.Reason); err != nil {
+		return err
+	}
+
+	if err := o.OrderRepository.Update(tx, order); err != nil {
+		o.Log.Warnf("Failed update order to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		o.Log.Warnf("Failed commit transaction : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	o.notifyStatus(ctx, order, from)
+
+	return nil
+}
+
+// Refund kembalikan dana order yang sudah dibayar, full (items kosong) atau sebagian per item.
+// Stok item yang di-refund dikembalikan, status jadi refunded kalau seluruh nominal sudah kembali.
+func (o *OrderUseCase) Refund(ctx context.Context, orderID, userID string, request *model.RefundOrderRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	err := o.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(o.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	id, err := uuid.Parse(orderID)
+	if err != nil {
+		return utils.ErrNotFound
+	}
+
+	tx := o.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	order, err := o.OrderRepository.FindByIDForUpdate(tx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			o.Log.Infof("order not found, id=%s", orderID)
+			return utils.ErrNotFound
+		}
+		o.Log.Warnf("Failed find order from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if order.Status == entity.OrderStatusRefunded {
+		return fmt.Errorf("%w: order %s is already fully refunded", utils.ErrConflict, order.InvoiceNumber)
+	}
+	if !order.CanTransitionTo(entity.OrderStatusRefunded) {
+		return fmt.Errorf("%w: order with status %s cannot be refunded", utils.ErrInvalidStatusTransition, order.Status)
+	}
+
+	items, err := o.OrderRepository.FindItemsByOrderID(tx, order.ID)
+	if err != nil {
+		o.Log.Warnf("Failed find order items from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	itemMap := make(map[uuid.UUID]*entity.OrderItem, len(items))
+	for i := range items {
+		itemMap[items[i].ID] = &items[i]
+	}
+
+	var amount int64
+	var refundItems []entity.RefundItem
+	if len(request.Items) == 0 {
+		// full refund, semua sisa qty & sisa nominal order
+		for i := range items {
+			qty := items[i].Qty - items[i].RefundedQty
+			if qty <= 0 {
+				continue
+			}
+			refundItems = append(refundItems, entity.RefundItem{
+				OrderItemID: items[i].ID,
+				Qty:         qty,
+				Amount:      items[i].Subtotal - items[i].RefundedAmount,
+			})
+		}
+		amount = order.Amount - order.RefundedAmount
+	} else {
+		requestedQty := make(map[uuid.UUID]int)
+		for _, reqItem := range request.Items {
+			itemID := utils.MustParseUUID(reqItem.OrderItemID)
+			item, ok := itemMap[itemID]
+			if !ok {
+				return fmt.Errorf("%w: order item %s not found in this order", utils.ErrValidation, reqItem.OrderItemID)
+			}
+			if _, ok := requestedQty[itemID]; !ok {
+				refundItems = append(refundItems, entity.RefundItem{OrderItemID: itemID})
+			}
+			requestedQty[itemID] += reqItem.Qty
+
+			if remaining := item.Qty - item.RefundedQty; requestedQty[itemID] > remaining {
+				return fmt.Errorf("%w: refund qty for %s exceeds remaining qty %d", utils.ErrValidation, item.ProductName, remaining)
+			}
+		}
+
+		var goodsAmount int64
+		for i := range refundItems {
+			item := itemMap[refundItems[i].OrderItemID]
+			refundItems[i].Qty = requestedQty[item.ID]
+			refundItems[i].Amount = item.Price * int64(refundItems[i].Qty)
+			goodsAmount += refundItems[i].Amount
+		}
+
+		// nominal refund ikut proporsi diskon, service charge, pajak & pembulatan order
+		amount = goodsAmount
+		if order.Subtotal > 0 {
+			amount = (goodsAmount*order.Amount + order.Subtotal/2) / order.Subtotal
+		}
+
+		// item terakhir yang di-refund ambil seluruh sisa nominal supaya tidak ada selisih pembulatan
+		fullyRefunded := true
+		for _, item := range items {
+			if item.Qty-item.RefundedQty != requestedQty[item.ID] {
+				fullyRefunded = false
+				break
+			}
+		}
+		if fullyRefunded {
+			amount = order.Amount - order.RefundedAmount
+		}
+	}
+
+	if amount <= 0 || order.RefundedAmount+amount > order.Amount {
+		return fmt.Errorf("%w: invalid refund amount %d, remaining %d", utils.ErrValidation, amount, order.Amount-order.RefundedAmount)
+	}
+
+	count, err := o.RefundRepository.CountByOrderID(tx, order.ID)
+	if err != nil {
+		o.Log.Warnf("Failed count refund from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	// order yang dibayar pakai wallet tidak punya transaksi di Midtrans, dana selalu kembali ke wallet
+	destination := request.Destination
+	switch {
+	case order.PaymentMethod == entity.PaymentMethodWallet:
+		destination = entity.RefundDestinationWallet
+	case destination == "":
+		destination = entity.RefundDestinationOriginal
+	}
+
+	// refund key sama kalau request diulang setelah gagal simpan, Midtrans tidak akan refund dua kali
+	refundKey := fmt.Sprintf("%s-R%d", order.InvoiceNumber, count+1)
+	actor := "cms:" + userID
+	refund := &entity.Refund{
+		OrderID:     order.ID,
+		RefundKey:   refundKey,
+		Amount:      amount,
+		Destination: destination,
+		Reason:      request.Reason,
+		Actor:       actor,
+		Items:       refundItems,
+	}
+
+	var resp *coreapi.RefundResponse
+	if destination == entity.RefundDestinationWallet {
+		if err := o.refundToWallet(tx, order, refund); err != nil {
+			return err
+		}
+	} else {
+		resp, err = o.Payment.Refund(order.InvoiceNumber, &coreapi.RefundReq{
+			RefundKey: refundKey,
+			Amount:    amount,
+			Reason:    request.Reason,
+		})
+		if err != nil {
+			return o.paymentError(err)
+		}
+		refund.Status = resp.TransactionStatus
+	}
+
+	if err := o.RefundRepository.Create(tx, refund); err != nil {
+		o.Log.Warnf("Failed create refund to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	for _, refundItem := range refundItems {
+		item := itemMap[refundItem.OrderItemID]
+		item.RefundedQty += refundItem.Qty
+		item.RefundedAmount += refundItem.Amount
+		if err := o.OrderRepository.UpdateItem(tx, item); err != nil {
+			o.Log.Warnf("Failed update order item to database : %+v", err)
+			return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+		}
+
+		// stok baru benar-benar berkurang kalau sudah committed
+		if order.StockStatus == entity.StockStatusCommitted {
+			if err := o.ProductRepository.RestoreStock(tx, item.ProductID, refundItem.Qty); err != nil {
+				o.Log.Warnf("Failed restore stock : %+v", err)
+				return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+			}
+		}
+	}
+
+	if resp != nil {
+		transactionID := order.TransactionID
+		if resp.TransactionID != "" {
+			transactionID = resp.TransactionID
+		}
+		rawResponse, _ := json.Marshal(resp)
+		paymentLog := &entity.PaymentLog{
+			OrderID:             order.ID,
+			MidtransTransaction: transactionID,
+			Status:              resp.TransactionStatus,
+			RawResponse:         datatypes.JSON(rawResponse),
+		}
+		if err := o.PaymentLogRepository.Create(tx, paymentLog); err != nil {
+			o.Log.Warnf("Failed create payment log to database : %+v", err)
+			return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+		}
+	}
+
+	if err := o.refundPoints(tx, order, amount, actor); err != nil {
+		o.Log.Warnf("Failed refund loyalty points : %+v", err)
+		return err
+	}
+
+	from := order.Status
+	order.RefundedAmount += amount
+	if order.RefundedAmount == order.Amount {
+		if err := o.transition(tx, order, entity.OrderStatusRefunded, actor, request.Reason); err != nil {
+			return err
+		}
+	}
+
+	if err := o.OrderRepository.Update(tx, order); err != nil {
+		o.Log.Warnf("Failed update order to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		o.Log.Warnf("Failed commit transaction : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	o.notifyStatus(ctx, order, from)
+	o.notifyOrder(ctx, notification.TemplateRefund, order, refund)
+
+	return nil
+}
+
+// refundToWallet kembalikan nominal refund ke saldo wallet customer pemilik order
+func (o *OrderUseCase) refundToWallet(tx *gorm.DB, order *entity.Order, refund *entity.Refund) error {
+	if _, err := o.CustomerRepository.FindById(tx, &entity.Customer{}, order.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: order %s has no registered customer to refund to wallet", utils.ErrValidation, order.InvoiceNumber)
+		}
+		o.Log.Warnf("Failed find customer from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	entry := &entity.WalletEntry{
+		Type:           entity.WalletEntryCredit,
+		Amount:         refund.Amount,
+		Source:         entity.WalletSourceRefund,
+		ReferenceID:    refund.RefundKey,
+		CounterAccount: entity.WalletAccountRefund,
+		Description:    "refund " + order.InvoiceNumber,
+		Actor:          refund.Actor,
+	}
+	if _, err := postWalletEntry(tx, o.WalletRepository, order.UserID, entry); err != nil {
+		o.Log.Warnf("Failed credit wallet : %+v", err)
+		return err
+	}
+
+	// status mengikuti penamaan Midtrans supaya laporan refund seragam
+	refund.Status = "partial_refund"
+	if order.RefundedAmount+refund.Amount == order.Amount {
+		refund.Status = "refund"
+	}
+	return nil
+}
+
+// notifyStatus kirim event realtime setiap status order berubah, plus email kalau order baru saja jadi paid / ready.
+// Dipanggil setelah commit.
+func (o *OrderUseCase) notifyStatus(ctx context.Context, order *entity.Order, from string) {
+	if order.Status == from {
+		return
+	}
+
+	o.publishOrder(ctx, order, from)
+
+	switch order.Status {
+	case entity.OrderStatusPaid:
+		o.notifyOrder(ctx, notification.TemplatePaymentReceived, order, nil)
+	case entity.OrderStatusReady:
+		o.notifyOrder(ctx, notification.TemplateOrderReady, order, nil)
+	}
+}
+
+// notifyOrder cari email customer lalu masukkan ke antrian email, gagal di sini tidak menggagalkan request
+func (o *OrderUseCase) notifyOrder(ctx context.Context, template string, order *entity.Order, refund *entity.Refund) {
+	customer, err := o.CustomerRepository.FindById(o.DB.WithContext(ctx), &entity.Customer{}, order.UserID)
+	if err != nil {
+		o.Log.Warnf("Failed find customer for %s email, invoice=%s : %+v", template, order.InvoiceNumber, err)
+		return
+	}
+
+	if err := o.loadItems(ctx, order); err != nil {
+		o.Log.Warnf("Failed find order items for %s email, invoice=%s : %+v", template, order.InvoiceNumber, err)
+	}
+
+	o.Mail.SendOrder(template, customer.Email, customer.Name, order, refund)
+}
+
+// publishOrder kirim perubahan status order ke layar realtime (barista & customer) lewat broker
+func (o *OrderUseCase) publishOrder(ctx context.Context, order *entity.Order, from string) {
+	if err := o.loadItems(ctx, order); err != nil {
+		o.Log.Warnf("Failed find order items for order event, invoice=%s : %+v", order.InvoiceNumber, err)
+	}
+
+	o.Events.Publish(ctx, &realtime.OrderEvent{
+		OrderID:    order.ID.String(),
+		CustomerID: order.UserID.String(),
+		FromStatus: from,
+		Status:     order.Status,
+		Order:      converter.OrderToResponse(order),
+		OccurredAt: time.Now(),
+	})
+}
+
+// loadItems isi item order kalau belum di-preload (order hasil lock tidak membawa item)
+func (o *OrderUseCase) loadItems(ctx context.Context, order *entity.Order) error {
+	if len(order.OrderItems) > 0 {
+		return nil
+	}
+
+	items, err := o.OrderRepository.FindItemsByOrderID(o.DB.WithContext(ctx), order.ID)
+	if err != nil {
+		return err
+	}
+	order.OrderItems = items
 	return nil
 }
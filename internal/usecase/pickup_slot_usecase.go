@@ -0,0 +1,287 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// pickupBookingDays slot pickup hanya bisa dipesan untuk hari ini & besok
+const pickupBookingDays = 2
+
+type PickupSlotUseCase struct {
+	DB                   *gorm.DB
+	Log                  *logrus.Logger
+	Validator            *utils.Validator
+	PickupSlotRepository *repository.PickupSlotRepository
+	OrderRepository      *repository.OrderRepository
+	Location             *time.Location
+}
+
+func NewPickupSlotUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
+	pickupSlotRepository *repository.PickupSlotRepository, orderRepository *repository.OrderRepository,
+	location *time.Location) *PickupSlotUseCase {
+	return &PickupSlotUseCase{
+		DB:                   db,
+		Log:                  logger,
+		Validator:            validator,
+		PickupSlotRepository: pickupSlotRepository,
+		OrderRepository:      orderRepository,
+		Location:             location,
+	}
+}
+
+func (p *PickupSlotUseCase) Create(ctx context.Context, request *model.CreatePickupSlotRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := p.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(p.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	if request.StartTime >= request.EndTime {
+		return fmt.Errorf("%w: end_time must be after start_time", utils.ErrValidation)
+	}
+
+	slot := &entity.PickupSlot{
+		Name:      request.Name,
+		StartTime: request.StartTime,
+		EndTime:   request.EndTime,
+		Capacity:  request.Capacity,
+		IsActive:  request.IsActive == nil || *request.IsActive,
+	}
+
+	if err := p.PickupSlotRepository.Create(p.DB.WithContext(ctx), slot); err != nil {
+		p.Log.Warnf("Failed create pickup slot to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+func (p *PickupSlotUseCase) FindAll(ctx context.Context, pagination *utils.PaginationRequest) ([]model.PickupSlotResponse, *utils.PaginationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var slots []entity.PickupSlot
+
+	total, err := p.PickupSlotRepository.FindAll(p.DB.WithContext(ctx), &slots, pagination)
+	if err != nil {
+		p.Log.Warnf("Failed find all pickup slot from database : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	responses := make([]model.PickupSlotResponse, len(slots))
+	for i, slot := range slots {
+		responses[i] = *converter.PickupSlotToResponse(&slot)
+	}
+
+	totalPage := int((total + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginationRes := &utils.PaginationResponse{
+		Page:      pagination.Page,
+		Limit:     pagination.Limit,
+		OrderBy:   pagination.OrderBy,
+		SortBy:    pagination.SortBy,
+		Search:    pagination.Search,
+		TotalData: total,
+		TotalPage: totalPage,
+	}
+
+	return responses, paginationRes, nil
+}
+
+func (p *PickupSlotUseCase) FindByID(ctx context.Context, slotID string) (*model.PickupSlotResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	slot, err := p.PickupSlotRepository.FindById(p.DB.WithContext(ctx), &entity.PickupSlot{}, slotID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.Log.Infof("Pickup slot not found, id=%s", slotID)
+			return nil, utils.ErrNotFound
+		}
+		p.Log.Warnf("Failed find pickup slot from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return converter.PickupSlotToResponse(slot), nil
+}
+
+// Update perubahan jam tidak memindahkan order yang sudah booking, pickup_at order tetap jam yang lama
+func (p *PickupSlotUseCase) Update(ctx context.Context, slotID string, request *model.UpdatePickupSlotRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	slot := &entity.PickupSlot{}
+	_, err := p.PickupSlotRepository.FindById(p.DB.WithContext(ctx), slot, slotID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.Log.Infof("Pickup slot not found, id=%s", slotID)
+			return utils.ErrNotFound
+		}
+		p.Log.Warnf("Failed find pickup slot from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	err = p.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(p.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	if request.StartTime >= request.EndTime {
+		return fmt.Errorf("%w: end_time must be after start_time", utils.ErrValidation)
+	}
+
+	slot.Name = request.Name
+	slot.StartTime = request.StartTime
+	slot.EndTime = request.EndTime
+	slot.Capacity = request.Capacity
+	if request.IsActive != nil {
+		slot.IsActive = *request.IsActive
+	}
+
+	if err := p.PickupSlotRepository.Update(p.DB.WithContext(ctx), slot); err != nil {
+		p.Log.Warnf("Failed update pickup slot to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+// Delete slot yang masih punya booking ke depan tidak bisa dihapus, nonaktifkan saja
+func (p *PickupSlotUseCase) Delete(ctx context.Context, slotID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	slot := &entity.PickupSlot{}
+	_, err := p.PickupSlotRepository.FindById(p.DB.WithContext(ctx), slot, slotID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			p.Log.Infof("Pickup slot not found, id=%s", slotID)
+			return utils.ErrNotFound
+		}
+		p.Log.Warnf("Failed find pickup slot from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	upcoming, err := p.OrderRepository.CountUpcomingPickupBookings(p.DB.WithContext(ctx), slot.ID, time.Now())
+	if err != nil {
+		p.Log.Warnf("Failed count pickup bookings from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if upcoming > 0 {
+		return fmt.Errorf("%w: pickup slot still has %d upcoming order(s), deactivate it instead", utils.ErrConflict, upcoming)
+	}
+
+	if err := p.PickupSlotRepository.Delete(p.DB.WithContext(ctx), slot); err != nil {
+		p.Log.Warnf("Failed delete pickup slot from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+// FindAvailable slot aktif untuk hari ini & besok beserta sisa kapasitasnya, slot yang sudah selesai tidak ditampilkan
+func (p *PickupSlotUseCase) FindAvailable(ctx context.Context) ([]model.PickupDayResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	slots, err := p.PickupSlotRepository.FindActive(p.DB.WithContext(ctx))
+	if err != nil {
+		p.Log.Warnf("Failed find active pickup slots from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	now := time.Now().In(p.Location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, p.Location)
+	counts, err := p.OrderRepository.CountPickupBookingsBetween(p.DB.WithContext(ctx), today, today.AddDate(0, 0, pickupBookingDays))
+	if err != nil {
+		p.Log.Warnf("Failed count pickup bookings from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	booked := make(map[string]int64, len(counts))
+	for _, count := range counts {
+		booked[pickupBookingKey(count.PickupSlotID, count.PickupAt)] += count.Total
+	}
+
+	days := make([]model.PickupDayResponse, 0, pickupBookingDays)
+	for i := 0; i < pickupBookingDays; i++ {
+		day := today.AddDate(0, 0, i)
+		response := model.PickupDayResponse{
+			Date:  day.Format("2006-01-02"),
+			Slots: []model.PickupSlotAvailableResponse{},
+		}
+
+		for _, slot := range slots {
+			if !slot.EndOn(day).After(now) {
+				continue
+			}
+			pickupAt := slot.StartOn(day)
+			total := booked[pickupBookingKey(slot.ID, pickupAt)]
+			response.Slots = append(response.Slots, model.PickupSlotAvailableResponse{
+				ID:        slot.ID.String(),
+				Name:      slot.Name,
+				StartTime: slot.StartTime,
+				EndTime:   slot.EndTime,
+				PickupAt:  pickupAt.String(),
+				Capacity:  slot.Capacity,
+				Booked:    total,
+				Available: max(int64(slot.Capacity)-total, 0),
+			})
+		}
+
+		days = append(days, response)
+	}
+
+	return days, nil
+}
+
+func pickupBookingKey(slotID uuid.UUID, pickupAt time.Time) string {
+	return slotID.String() + "@" + pickupAt.UTC().Format(time.RFC3339)
+}
+
+// pickupDay tanggal pickup dari request (timezone toko), harus masih di dalam jendela booking
+func pickupDay(date string, now time.Time, location *time.Location) (time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid pickup_date %s", utils.ErrValidation, date)
+	}
+
+	now = now.In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	if day.Before(today) || !day.Before(today.AddDate(0, 0, pickupBookingDays)) {
+		return time.Time{}, fmt.Errorf("%w: pickup_date must be within the next %d day(s)", utils.ErrValidation, pickupBookingDays)
+	}
+	return day, nil
+}
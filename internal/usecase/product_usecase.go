@@ -197,6 +197,9 @@ func (p *ProductUseCase) Update(ctx context.Context, productID string, request *
 	if request.CategoryID != uuid.Nil {
 		product.CategoryID = request.CategoryID
 	}
+	if request.IsActive != nil {
+		product.IsActive = *request.IsActive
+	}
 
 	// check duplicate
 	exists, err := p.ProductRepository.ExistsByName(p.DB.WithContext(ctx), request.Name)
@@ -207,7 +210,7 @@ func (p *ProductUseCase) Update(ctx context.Context, productID string, request *
 		return fmt.Errorf("%w: %s", utils.ErrConflict, "product name already exist")
 	}
 
-	err = p.ProductRepository.Update(p.DB.WithContext(ctx), product)
+	err = p.ProductRepository.UpdateDetail(p.DB.WithContext(ctx), product, request.Stock != 0)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			p.Log.Infof("product not found, id=%s", productID)
@@ -293,24 +296,28 @@ func (p *ProductUseCase) SetSpecialProduct(ctx context.Context, request *model.U
 		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
 	}
 
-	prevSpecialProduct, err := p.ProductRepository.FindSpecialProduct(p.DB.WithContext(ctx))
+	tx := p.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	// ✅ Hanya kolom is_special yang ditulis, stock & reserved bisa berubah oleh checkout yang jalan bersamaan
+	prevSpecialProduct, err := p.ProductRepository.FindSpecialProduct(tx)
 	if err == nil && prevSpecialProduct != nil {
-		prevSpecialProduct.IsSpecial = false
-		if err := p.ProductRepository.Update(p.DB.WithContext(ctx), prevSpecialProduct); err != nil {
+		if err := p.ProductRepository.UpdateSpecial(tx, prevSpecialProduct.ID, false); err != nil {
 			return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
 		}
 	} else if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
 	}
 
-	product.IsSpecial = request.IsSpecial
-	if err := p.ProductRepository.Update(p.DB.WithContext(ctx), product); err != nil {
+	if err := p.ProductRepository.UpdateSpecial(tx, product.ID, request.IsSpecial); err != nil {
 		p.Log.Warnf("Failed update special product : %+v", err)
 		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
 	}
 
-	prevSpecialProduct.IsSpecial = false
-	product.IsSpecial = request.IsSpecial
+	if err := tx.Commit().Error; err != nil {
+		p.Log.Warnf("Failed commit transaction : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
 
 	return nil
 }
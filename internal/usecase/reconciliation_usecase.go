@@ -0,0 +1,535 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// maksimal rentang tanggal satu kali rekonsiliasi, tiap order = satu request ke Midtrans
+const reconciliationMaxDays = 31
+
+// run manual yang boleh antri, diproses satu per satu oleh ReconciliationWorker
+const reconciliationQueueSize = 10
+
+type ReconciliationUseCase struct {
+	DB                       *gorm.DB
+	Log                      *logrus.Logger
+	Validator                *utils.Validator
+	ReconciliationRepository *repository.ReconciliationRepository
+	OrderRepository          *repository.OrderRepository
+	PaymentLogRepository     *repository.PaymentLogRepository
+	WalletRepository         *repository.WalletRepository
+	OrderUseCase             *OrderUseCase
+	WalletUseCase            *WalletUseCase
+	Payment                  service.PaymentProvider
+	Location                 *time.Location
+	queued                   chan struct{}
+}
+
+func NewReconciliationUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
+	reconciliationRepository *repository.ReconciliationRepository, orderRepository *repository.OrderRepository,
+	paymentLogRepository *repository.PaymentLogRepository, walletRepository *repository.WalletRepository,
+	orderUseCase *OrderUseCase, walletUseCase *WalletUseCase,
+	payment service.PaymentProvider, location *time.Location) *ReconciliationUseCase {
+	return &ReconciliationUseCase{
+		DB:                       db,
+		Log:                      logger,
+		Validator:                validator,
+		ReconciliationRepository: reconciliationRepository,
+		OrderRepository:          orderRepository,
+		PaymentLogRepository:     paymentLogRepository,
+		WalletRepository:         walletRepository,
+		OrderUseCase:             orderUseCase,
+		WalletUseCase:            walletUseCase,
+		Payment:                  payment,
+		Location:                 location,
+		queued:                   make(chan struct{}, 1),
+	}
+}
+
+// Run daftarkan rekonsiliasi manual dari CMS lalu langsung return, prosesnya jalan di ReconciliationWorker.
+// Antriannya row queued di database, jadi tidak hilang kalau server restart sebelum diproses.
+// Client polling GET /reconciliations/:id sampai status done / failed.
+func (r *ReconciliationUseCase) Run(ctx context.Context, trigger string, request *model.RunReconciliationRequest) (*model.ReconciliationRunResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	queued, err := r.ReconciliationRepository.CountQueued(r.DB.WithContext(ctx))
+	if err != nil {
+		r.Log.Warnf("Failed count queued reconciliation runs from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if queued >= reconciliationQueueSize {
+		return nil, fmt.Errorf("%w: too many reconciliations in progress, try again later", utils.ErrConflict)
+	}
+
+	run, err := r.create(ctx, trigger, request, entity.ReconciliationStatusQueued)
+	if err != nil {
+		return nil, err
+	}
+
+	// bangunkan worker, kalau sinyal sebelumnya belum dibaca run ini ikut terambil di putaran yang sama
+	select {
+	case r.queued <- struct{}{}:
+	default:
+	}
+
+	return converter.ReconciliationRunToResponse(run), nil
+}
+
+// Queued sinyal ada run manual baru, dibaca ReconciliationWorker
+func (r *ReconciliationUseCase) Queued() <-chan struct{} {
+	return r.queued
+}
+
+// RunDaily rekonsiliasi order & top up kemarin (timezone toko), dipanggil worker malam hari
+func (r *ReconciliationUseCase) RunDaily(ctx context.Context, now time.Time) (*model.ReconciliationRunResponse, error) {
+	day := now.In(r.Location).AddDate(0, 0, -1).Format("2006-01-02")
+	run, err := r.create(ctx, entity.ActorReconciliation, &model.RunReconciliationRequest{
+		DateFrom: day,
+		DateTo:   day,
+		AutoFix:  true,
+	}, entity.ReconciliationStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.execute(ctx, run); err != nil {
+		return nil, err
+	}
+	return r.FindByID(ctx, run.ID.String())
+}
+
+// create validasi rentang tanggal lalu simpan run dengan status awal (queued untuk manual, running untuk harian)
+func (r *ReconciliationUseCase) create(ctx context.Context, trigger string, request *model.RunReconciliationRequest,
+	status string) (*entity.ReconciliationRun, error) {
+	err := r.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(r.Validator.Translator))
+			}
+			return nil, fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return nil, fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	from, to := r.dateRange(request.DateFrom, request.DateTo)
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: date_to must not be before date_from", utils.ErrValidation)
+	}
+	if to.Sub(from) > reconciliationMaxDays*24*time.Hour {
+		return nil, fmt.Errorf("%w: date range must not exceed %d days", utils.ErrValidation, reconciliationMaxDays)
+	}
+
+	run := &entity.ReconciliationRun{
+		DateFrom: request.DateFrom,
+		DateTo:   request.DateTo,
+		Trigger:  trigger,
+		AutoFix:  request.AutoFix,
+		Status:   status,
+	}
+	if err := r.ReconciliationRepository.Create(r.DB.WithContext(ctx), run); err != nil {
+		r.Log.Warnf("Failed create reconciliation run to database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	return run, nil
+}
+
+// dateRange [from, to) dari tanggal request, batas hari ikut timezone toko sama seperti nomor invoice
+func (r *ReconciliationUseCase) dateRange(dateFrom, dateTo string) (time.Time, time.Time) {
+	from, _ := time.ParseInLocation("2006-01-02", dateFrom, r.Location)
+	to, _ := time.ParseInLocation("2006-01-02", dateTo, r.Location)
+	return from, to.AddDate(0, 0, 1)
+}
+
+// ProcessNext ambil satu run queued paling lama lalu proses. Return false kalau antrian sudah kosong.
+// Dipanggil worker saat start (run yang tertinggal sebelum restart) dan setiap ada sinyal Queued.
+func (r *ReconciliationUseCase) ProcessNext(ctx context.Context) (bool, error) {
+	run, err := r.ReconciliationRepository.ClaimNextQueued(r.DB.WithContext(ctx))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		r.Log.Warnf("Failed claim queued reconciliation run from database : %+v", err)
+		return false, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return true, r.execute(ctx, run)
+}
+
+// execute cocokkan order & top up wallet di rentang tanggal run dengan status transaksi di payment provider.
+// Hasil akhir (done / failed) selalu disimpan, termasuk kalau terhenti karena context di-cancel.
+func (r *ReconciliationUseCase) execute(ctx context.Context, run *entity.ReconciliationRun) error {
+	err := r.process(ctx, run)
+	switch {
+	case err != nil:
+		run.Status, run.Error = entity.ReconciliationStatusFailed, err.Error()
+	case ctx.Err() != nil:
+		run.Status, run.Error = entity.ReconciliationStatusFailed, "interrupted: "+ctx.Err().Error()
+	default:
+		run.Status = entity.ReconciliationStatusDone
+	}
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+
+	// ctx bisa sudah di-cancel (shutdown), status akhir tetap harus tersimpan
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := r.DB.WithContext(saveCtx).Omit("Items").Save(run).Error; err != nil {
+		r.Log.Warnf("Failed update reconciliation run to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	r.Log.Infof("Reconciliation %s - %s %s: checked=%d mismatch=%d fixed=%d error=%d",
+		run.DateFrom, run.DateTo, run.Status, run.TotalChecked, run.TotalMismatch, run.TotalFixed, run.TotalError)
+	return err
+}
+
+func (r *ReconciliationUseCase) process(ctx context.Context, run *entity.ReconciliationRun) error {
+	from, to := r.dateRange(run.DateFrom, run.DateTo)
+
+	orders, err := r.OrderRepository.FindCreatedBetween(r.DB.WithContext(ctx), from, to)
+	if err != nil {
+		r.Log.Warnf("Failed find orders for reconciliation : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	for i := range orders {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		item, err := r.checkOrder(ctx, &orders[i], run.AutoFix)
+		if err := r.record(ctx, run, orders[i].InvoiceNumber, item, err); err != nil {
+			return err
+		}
+	}
+
+	topUps, err := r.WalletRepository.FindTopUpsCreatedBetween(r.DB.WithContext(ctx), from, to)
+	if err != nil {
+		r.Log.Warnf("Failed find top ups for reconciliation : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	for i := range topUps {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		item, err := r.checkTopUp(ctx, &topUps[i], run.AutoFix)
+		if err := r.record(ctx, run, topUps[i].InvoiceNumber, item, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// record hitung hasil cek satu transaksi ke run, item selisih disimpan ke database
+func (r *ReconciliationUseCase) record(ctx context.Context, run *entity.ReconciliationRun, invoiceNumber string,
+	item *entity.ReconciliationItem, checkErr error) error {
+	run.TotalChecked++
+	if checkErr != nil {
+		r.Log.Warnf("Failed reconcile transaction, invoice=%s : %+v", invoiceNumber, checkErr)
+		run.TotalError++
+		return nil
+	}
+	if item == nil {
+		return nil
+	}
+
+	item.RunID = run.ID
+	if err := r.ReconciliationRepository.CreateItem(r.DB.WithContext(ctx), item); err != nil {
+		r.Log.Warnf("Failed create reconciliation item to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	run.Items = append(run.Items, *item)
+	run.TotalMismatch++
+	if item.Fixed {
+		run.TotalFixed++
+	}
+	return nil
+}
+
+// checkOrder return nil kalau order sesuai dengan provider. Yang di-auto-fix hanya yang aman:
+// order pending yang di Midtrans sudah final (lewat state machine yang sama dengan notifikasi)
+// dan payment log yang ketinggalan. Selisih amount & status final yang berbeda hanya dilaporkan.
+func (r *ReconciliationUseCase) checkOrder(ctx context.Context, order *entity.Order, autoFix bool) (*entity.ReconciliationItem, error) {
+	// dibayar pakai saldo wallet, tidak ada transaksinya di Midtrans
+	if order.PaymentMethod == entity.PaymentMethodWallet {
+		return nil, nil
+	}
+
+	item := &entity.ReconciliationItem{
+		OrderID:       &order.ID,
+		InvoiceNumber: order.InvoiceNumber,
+		OrderStatus:   order.Status,
+		OrderAmount:   order.Amount,
+	}
+
+	lastLog, err := r.PaymentLogRepository.FindLatestByOrderID(r.DB.WithContext(ctx), order.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if lastLog != nil {
+		item.LastLogStatus = lastLog.Status
+	}
+
+	resp, err := r.Payment.Status(order.InvoiceNumber)
+	if err != nil {
+		if !errors.Is(err, utils.ErrPaymentNotFound) {
+			return nil, fmt.Errorf("%w: %v", utils.ErrIntegration, err)
+		}
+		// order yang belum dibayar memang wajar tidak ada di Midtrans (ex: Snap yang tidak pernah dibuka)
+		if !isPaidStatus(order.Status) {
+			return nil, nil
+		}
+		item.Type = entity.MismatchUnknownTransaction
+		item.Note = "transaction not found at payment provider"
+		return item, nil
+	}
+
+	amountFloat, _ := strconv.ParseFloat(resp.GrossAmount, 64)
+	item.ProviderStatus = resp.TransactionStatus
+	item.ProviderAmount = int64(amountFloat)
+	providerStatus := utils.MapMidtransStatus(resp.TransactionStatus, resp.FraudStatus)
+
+	switch {
+	case item.ProviderAmount != order.Amount:
+		item.Type = entity.MismatchAmount
+		item.Note = "gross amount differs, check manually"
+
+	case order.Status == entity.OrderStatusPending && providerStatus != entity.OrderStatusPending:
+		item.Type = entity.MismatchStatus
+		if providerStatus == entity.OrderStatusPaid {
+			item.Type = entity.MismatchPaidButPending
+		}
+		if !autoFix {
+			break
+		}
+
+		fixed, err := r.OrderUseCase.ReconcilePendingOrder(ctx, order.ID, resp, entity.ActorReconciliation)
+		if err != nil {
+			item.Note = "auto fix failed: " + err.Error()
+			break
+		}
+		item.Fixed = fixed
+		if fixed {
+			item.Note = "order moved to " + providerStatus
+			r.Log.Infof("Reconciliation fixed invoice=%s: pending -> %s", order.InvoiceNumber, providerStatus)
+		} else {
+			item.Note = "order already changed, skipped"
+		}
+
+	case !statusMatches(order, providerStatus):
+		item.Type = entity.MismatchStatus
+		item.Note = "order status differs from provider, check manually"
+
+	case lastLog == nil || lastLog.Status != resp.TransactionStatus:
+		item.Type = entity.MismatchMissingPaymentLog
+		if !autoFix {
+			break
+		}
+
+		rawResponse, _ := json.Marshal(resp)
+		paymentLog := &entity.PaymentLog{
+			OrderID:             order.ID,
+			MidtransTransaction: resp.TransactionID,
+			Status:              resp.TransactionStatus,
+			RawResponse:         datatypes.JSON(rawResponse),
+		}
+		if err := r.PaymentLogRepository.Create(r.DB.WithContext(ctx), paymentLog); err != nil {
+			item.Note = "auto fix failed: " + err.Error()
+			break
+		}
+		item.Fixed = true
+		item.Note = "payment log " + resp.TransactionStatus + " recorded"
+		r.Log.Infof("Reconciliation fixed invoice=%s: recorded payment log %s", order.InvoiceNumber, resp.TransactionStatus)
+
+	default:
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+// checkTopUp sama seperti checkOrder untuk top up wallet. Top up tidak punya payment log,
+// jadi yang di-auto-fix hanya top up pending yang di Midtrans sudah final (saldo ikut dikredit kalau paid).
+func (r *ReconciliationUseCase) checkTopUp(ctx context.Context, topUp *entity.WalletTopUp, autoFix bool) (*entity.ReconciliationItem, error) {
+	item := &entity.ReconciliationItem{
+		TopUpID:       &topUp.ID,
+		InvoiceNumber: topUp.InvoiceNumber,
+		OrderStatus:   topUp.Status,
+		OrderAmount:   topUp.Amount,
+	}
+
+	resp, err := r.Payment.Status(topUp.InvoiceNumber)
+	if err != nil {
+		if !errors.Is(err, utils.ErrPaymentNotFound) {
+			return nil, fmt.Errorf("%w: %v", utils.ErrIntegration, err)
+		}
+		if topUp.Status != entity.TopUpStatusPaid {
+			return nil, nil
+		}
+		item.Type = entity.MismatchUnknownTransaction
+		item.Note = "transaction not found at payment provider"
+		return item, nil
+	}
+
+	amountFloat, _ := strconv.ParseFloat(resp.GrossAmount, 64)
+	item.ProviderStatus = resp.TransactionStatus
+	item.ProviderAmount = int64(amountFloat)
+	providerStatus := utils.MapMidtransStatus(resp.TransactionStatus, resp.FraudStatus)
+
+	switch {
+	case item.ProviderAmount != topUp.Amount:
+		item.Type = entity.MismatchAmount
+		item.Note = "gross amount differs, check manually"
+
+	case topUp.Status == entity.TopUpStatusPending && providerStatus != entity.OrderStatusPending:
+		item.Type = entity.MismatchStatus
+		if providerStatus == entity.OrderStatusPaid {
+			item.Type = entity.MismatchPaidButPending
+		}
+		if !autoFix {
+			break
+		}
+
+		fixed, err := r.WalletUseCase.ReconcilePendingTopUp(ctx, topUp.InvoiceNumber, resp, entity.ActorReconciliation)
+		if err != nil {
+			item.Note = "auto fix failed: " + err.Error()
+			break
+		}
+		item.Fixed = fixed
+		if fixed {
+			item.Note = "top up moved to " + providerStatus
+			r.Log.Infof("Reconciliation fixed top up invoice=%s: pending -> %s", topUp.InvoiceNumber, providerStatus)
+		} else {
+			item.Note = "top up already changed, skipped"
+		}
+
+	case topUp.Status != providerStatus:
+		item.Type = entity.MismatchStatus
+		item.Note = "top up status differs from provider, check manually"
+
+	default:
+		return nil, nil
+	}
+
+	return item, nil
+}
+
+// isPaidStatus order yang uangnya sudah (pernah) diterima
+func isPaidStatus(status string) bool {
+	switch status {
+	case entity.OrderStatusPaid, entity.OrderStatusPreparing, entity.OrderStatusReady,
+		entity.OrderStatusCompleted, entity.OrderStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// statusMatches status fulfillment (preparing, ready, completed) di Midtrans tetap terlihat sebagai paid.
+// Order yang di-refund ke wallet juga tetap settlement di Midtrans, karena uangnya tidak dikembalikan lewat Midtrans.
+func statusMatches(order *entity.Order, providerStatus string) bool {
+	if order.Status == providerStatus {
+		return true
+	}
+	if providerStatus != entity.OrderStatusPaid || !isPaidStatus(order.Status) {
+		return false
+	}
+	if order.Status != entity.OrderStatusRefunded {
+		return true
+	}
+	return refundedToWallet(order)
+}
+
+// refundedToWallet semua refund order masuk ke wallet, tidak ada yang diproses Midtrans
+func refundedToWallet(order *entity.Order) bool {
+	if len(order.Refunds) == 0 {
+		return false
+	}
+	for _, refund := range order.Refunds {
+		if refund.Destination != entity.RefundDestinationWallet {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *ReconciliationUseCase) FindAll(ctx context.Context, pagination *utils.PaginationRequest) ([]model.ReconciliationRunResponse, *utils.PaginationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// laporan terbaru dulu, kolom sort tidak diambil dari client
+	pagination.OrderBy = "created_at"
+	pagination.SortBy = "desc"
+
+	var runs []entity.ReconciliationRun
+
+	total, err := r.ReconciliationRepository.FindAll(r.DB.WithContext(ctx), &runs, pagination)
+	if err != nil {
+		r.Log.Warnf("Failed find all reconciliation run from database : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	responses := make([]model.ReconciliationRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = *converter.ReconciliationRunToResponse(&run)
+	}
+
+	totalPage := int((total + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginationRes := &utils.PaginationResponse{
+		Page:      pagination.Page,
+		Limit:     pagination.Limit,
+		OrderBy:   pagination.OrderBy,
+		SortBy:    pagination.SortBy,
+		Search:    pagination.Search,
+		TotalData: total,
+		TotalPage: totalPage,
+	}
+
+	return responses, paginationRes, nil
+}
+
+func (r *ReconciliationUseCase) FindByID(ctx context.Context, id string) (*model.ReconciliationRunResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, utils.ErrNotFound
+	}
+
+	run, err := r.ReconciliationRepository.FindDetailByID(r.DB.WithContext(ctx), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			r.Log.Infof("reconciliation run not found, id=%s", id)
+			return nil, utils.ErrNotFound
+		}
+		r.Log.Warnf("Failed find reconciliation run from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return converter.ReconciliationRunToResponse(run), nil
+}
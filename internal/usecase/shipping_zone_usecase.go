@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ShippingZoneUseCase struct {
+	DB                     *gorm.DB
+	Log                    *logrus.Logger
+	Validator              *utils.Validator
+	ShippingZoneRepository *repository.ShippingZoneRepository
+}
+
+func NewShippingZoneUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
+	shippingZoneRepository *repository.ShippingZoneRepository) *ShippingZoneUseCase {
+	return &ShippingZoneUseCase{
+		DB:                     db,
+		Log:                    logger,
+		Validator:              validator,
+		ShippingZoneRepository: shippingZoneRepository,
+	}
+}
+
+func (s *ShippingZoneUseCase) Create(ctx context.Context, request *model.CreateShippingZoneRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := s.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(s.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	zone := &entity.ShippingZone{
+		Name:             request.Name,
+		City:             strings.TrimSpace(request.City),
+		PostalCodePrefix: request.PostalCodePrefix,
+		Fee:              request.Fee,
+		MinOrder:         request.MinOrder,
+		FreeThreshold:    request.FreeThreshold,
+		IsActive:         request.IsActive == nil || *request.IsActive,
+	}
+
+	if err := s.ShippingZoneRepository.Create(s.DB.WithContext(ctx), zone); err != nil {
+		s.Log.Warnf("Failed create shipping zone to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+func (s *ShippingZoneUseCase) FindAll(ctx context.Context, pagination *utils.PaginationRequest) ([]model.ShippingZoneResponse, *utils.PaginationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var zones []entity.ShippingZone
+
+	total, err := s.ShippingZoneRepository.FindAll(s.DB.WithContext(ctx), &zones, pagination)
+	if err != nil {
+		s.Log.Warnf("Failed find all shipping zone from database : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	responses := make([]model.ShippingZoneResponse, len(zones))
+	for i, zone := range zones {
+		responses[i] = *converter.ShippingZoneToResponse(&zone)
+	}
+
+	totalPage := int((total + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginationRes := &utils.PaginationResponse{
+		Page:      pagination.Page,
+		Limit:     pagination.Limit,
+		OrderBy:   pagination.OrderBy,
+		SortBy:    pagination.SortBy,
+		Search:    pagination.Search,
+		TotalData: total,
+		TotalPage: totalPage,
+	}
+
+	return responses, paginationRes, nil
+}
+
+func (s *ShippingZoneUseCase) FindByID(ctx context.Context, zoneID string) (*model.ShippingZoneResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	zone, err := s.ShippingZoneRepository.FindById(s.DB.WithContext(ctx), &entity.ShippingZone{}, zoneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.Log.Infof("Shipping zone not found, id=%s", zoneID)
+			return nil, utils.ErrNotFound
+		}
+		s.Log.Warnf("Failed find shipping zone from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return converter.ShippingZoneToResponse(zone), nil
+}
+
+func (s *ShippingZoneUseCase) Update(ctx context.Context, zoneID string, request *model.UpdateShippingZoneRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	zone := &entity.ShippingZone{}
+	_, err := s.ShippingZoneRepository.FindById(s.DB.WithContext(ctx), zone, zoneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.Log.Infof("Shipping zone not found, id=%s", zoneID)
+			return utils.ErrNotFound
+		}
+		s.Log.Warnf("Failed find shipping zone from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	err = s.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(s.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	zone.Name = request.Name
+	zone.City = strings.TrimSpace(request.City)
+	zone.PostalCodePrefix = request.PostalCodePrefix
+	zone.Fee = request.Fee
+	zone.MinOrder = request.MinOrder
+	zone.FreeThreshold = request.FreeThreshold
+	if request.IsActive != nil {
+		zone.IsActive = *request.IsActive
+	}
+
+	if err := s.ShippingZoneRepository.Update(s.DB.WithContext(ctx), zone); err != nil {
+		s.Log.Warnf("Failed update shipping zone to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+func (s *ShippingZoneUseCase) Delete(ctx context.Context, zoneID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	zone := &entity.ShippingZone{}
+	_, err := s.ShippingZoneRepository.FindById(s.DB.WithContext(ctx), zone, zoneID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			s.Log.Infof("Shipping zone not found, id=%s", zoneID)
+			return utils.ErrNotFound
+		}
+		s.Log.Warnf("Failed find shipping zone from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if err := s.ShippingZoneRepository.Delete(s.DB.WithContext(ctx), zone); err != nil {
+		s.Log.Warnf("Failed delete shipping zone from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,305 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type VoucherUseCase struct {
+	DB                 *gorm.DB
+	Log                *logrus.Logger
+	Validator          *utils.Validator
+	VoucherRepository  *repository.VoucherRepository
+	ProductRepository  *repository.ProductRepository
+	CategoryRepository *repository.CategoryRepository
+}
+
+func NewVoucherUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
+	voucherRepository *repository.VoucherRepository, productRepository *repository.ProductRepository,
+	categoryRepository *repository.CategoryRepository) *VoucherUseCase {
+	return &VoucherUseCase{
+		DB:                 db,
+		Log:                logger,
+		Validator:          validator,
+		VoucherRepository:  voucherRepository,
+		ProductRepository:  productRepository,
+		CategoryRepository: categoryRepository,
+	}
+}
+
+func (v *VoucherUseCase) Create(ctx context.Context, request *model.CreateVoucherRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := v.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(v.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	tx := v.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	voucher := &entity.Voucher{IsActive: true}
+	products, categories, err := v.fill(tx, voucher, request)
+	if err != nil {
+		return err
+	}
+
+	if err := v.VoucherRepository.Create(tx, voucher); err != nil {
+		v.Log.Warnf("Failed create voucher to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if err := v.VoucherRepository.ReplaceRestrictions(tx, voucher, products, categories); err != nil {
+		v.Log.Warnf("Failed save voucher restrictions to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		v.Log.Warnf("Failed commit transaction : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+func (v *VoucherUseCase) FindAll(ctx context.Context, pagination *utils.PaginationRequest) ([]model.VoucherResponse, *utils.PaginationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var vouchers []entity.Voucher
+
+	total, err := v.VoucherRepository.FindAll(v.DB.WithContext(ctx), &vouchers, pagination)
+	if err != nil {
+		v.Log.Warnf("Failed find all voucher from database : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	responses := make([]model.VoucherResponse, len(vouchers))
+	for i, voucher := range vouchers {
+		responses[i] = *converter.VoucherToResponse(&voucher)
+	}
+
+	totalPage := int((total + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	paginationRes := &utils.PaginationResponse{
+		Page:      pagination.Page,
+		Limit:     pagination.Limit,
+		OrderBy:   pagination.OrderBy,
+		SortBy:    pagination.SortBy,
+		Search:    pagination.Search,
+		TotalData: total,
+		TotalPage: totalPage,
+	}
+
+	return responses, paginationRes, nil
+}
+
+func (v *VoucherUseCase) FindByID(ctx context.Context, voucherID string) (*model.VoucherResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	voucher, err := v.VoucherRepository.FindDetailByID(v.DB.WithContext(ctx), voucherID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			v.Log.Infof("Voucher not found, id=%s", voucherID)
+			return nil, utils.ErrNotFound
+		}
+		v.Log.Warnf("Failed find voucher from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return converter.VoucherToResponse(voucher), nil
+}
+
+func (v *VoucherUseCase) Update(ctx context.Context, voucherID string, request *model.UpdateVoucherRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err := v.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(v.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	tx := v.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	// ✅ Lock voucher dulu, Save di bawah menulis ulang semua kolom termasuk used_count
+	voucher, err := v.VoucherRepository.FindByIDForUpdate(tx, voucherID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			v.Log.Infof("Voucher not found, id=%s", voucherID)
+			return utils.ErrNotFound
+		}
+		v.Log.Warnf("Failed find voucher from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	// request create & update punya field yang sama
+	createRequest := model.CreateVoucherRequest(*request)
+	products, categories, err := v.fill(tx, voucher, &createRequest)
+	if err != nil {
+		return err
+	}
+
+	if err := v.VoucherRepository.Update(tx, voucher); err != nil {
+		v.Log.Warnf("Failed update voucher to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if err := v.VoucherRepository.ReplaceRestrictions(tx, voucher, products, categories); err != nil {
+		v.Log.Warnf("Failed save voucher restrictions to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		v.Log.Warnf("Failed commit transaction : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+func (v *VoucherUseCase) Delete(ctx context.Context, voucherID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	tx := v.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	voucher := &entity.Voucher{}
+	_, err := v.VoucherRepository.FindById(tx, voucher, voucherID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			v.Log.Infof("Voucher not found, id=%s", voucherID)
+			return utils.ErrNotFound
+		}
+		v.Log.Warnf("Failed find voucher from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	// voucher yang sudah pernah dipakai tetap disimpan untuk histori order, cukup dinonaktifkan
+	redeemed, err := v.VoucherRepository.CountRedemptions(tx, voucher.ID)
+	if err != nil {
+		v.Log.Warnf("Failed count voucher redemptions : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if redeemed > 0 {
+		return fmt.Errorf("%w: voucher already redeemed, deactivate it instead", utils.ErrConflict)
+	}
+
+	if err := v.VoucherRepository.ReplaceRestrictions(tx, voucher, nil, nil); err != nil {
+		v.Log.Warnf("Failed delete voucher restrictions : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if err := v.VoucherRepository.Delete(tx, voucher); err != nil {
+		v.Log.Warnf("Failed delete voucher from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		v.Log.Warnf("Failed commit transaction : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+// fill validasi aturan voucher lalu salin request ke entity, return produk & kategori restriksi
+func (v *VoucherUseCase) fill(tx *gorm.DB, voucher *entity.Voucher, request *model.CreateVoucherRequest) ([]entity.Product, []entity.Category, error) {
+	code := strings.ToUpper(request.Code)
+	if request.Type == entity.VoucherTypePercentage && request.Value > 100 {
+		return nil, nil, fmt.Errorf("%w: percentage value must be between 1 and 100", utils.ErrValidation)
+	}
+
+	var startsAt, endsAt *time.Time
+	if request.StartsAt != "" {
+		t, _ := time.Parse(time.RFC3339, request.StartsAt)
+		startsAt = &t
+	}
+	if request.EndsAt != "" {
+		t, _ := time.Parse(time.RFC3339, request.EndsAt)
+		endsAt = &t
+	}
+	if startsAt != nil && endsAt != nil && !endsAt.After(*startsAt) {
+		return nil, nil, fmt.Errorf("%w: ends_at must be after starts_at", utils.ErrValidation)
+	}
+
+	exists, err := v.VoucherRepository.ExistsByCode(tx, code, voucher.ID)
+	if err != nil {
+		v.Log.Warnf("Failed check voucher code : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if exists {
+		return nil, nil, fmt.Errorf("%w: voucher code already exist", utils.ErrConflict)
+	}
+
+	var products []entity.Product
+	if len(request.ProductIDs) > 0 {
+		ids := utils.MustParseUUIDs(request.ProductIDs)
+		products, err = v.ProductRepository.FindByIDs(tx, ids)
+		if err != nil {
+			v.Log.Warnf("Failed find products from database : %+v", err)
+			return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+		}
+		if len(products) != len(ids) {
+			return nil, nil, fmt.Errorf("%w: some product_ids not found", utils.ErrValidation)
+		}
+	}
+
+	var categories []entity.Category
+	if len(request.CategoryIDs) > 0 {
+		ids := utils.MustParseUUIDs(request.CategoryIDs)
+		categories, err = v.CategoryRepository.FindByIDs(tx, ids)
+		if err != nil {
+			v.Log.Warnf("Failed find categories from database : %+v", err)
+			return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+		}
+		if len(categories) != len(ids) {
+			return nil, nil, fmt.Errorf("%w: some category_ids not found", utils.ErrValidation)
+		}
+	}
+
+	voucher.Code = code
+	voucher.Name = request.Name
+	voucher.Description = request.Description
+	voucher.Type = request.Type
+	voucher.Value = request.Value
+	voucher.MinSpend = request.MinSpend
+	voucher.MaxDiscount = request.MaxDiscount
+	voucher.UsageLimit = request.UsageLimit
+	voucher.PerCustomerLimit = request.PerCustomerLimit
+	voucher.StartsAt = startsAt
+	voucher.EndsAt = endsAt
+	if request.IsActive != nil {
+		voucher.IsActive = *request.IsActive
+	}
+
+	return products, categories, nil
+}
@@ -0,0 +1,536 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model/converter"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/repository"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/service"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/utils"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type WalletUseCase struct {
+	DB                 *gorm.DB
+	Log                *logrus.Logger
+	Validator          *utils.Validator
+	WalletRepository   *repository.WalletRepository
+	CustomerRepository *repository.CustomerRepository
+	InvoiceCounter     *repository.InvoiceCounterRepository
+	InvoiceFormat      *utils.InvoiceFormat // prefix sendiri (ex: TOP), supaya notifikasi Midtrans bisa dibedakan dari order
+	OrderUseCase       *OrderUseCase        // charge Core API / Snap sama persis dengan order
+	Payment            service.PaymentProvider
+	CheckoutMode       string
+	MinTopUp           int64
+	MaxTopUp           int64
+}
+
+func NewWalletUseCase(db *gorm.DB, logger *logrus.Logger, validator *utils.Validator,
+	walletRepository *repository.WalletRepository, customerRepository *repository.CustomerRepository,
+	invoiceCounter *repository.InvoiceCounterRepository, invoiceFormat *utils.InvoiceFormat,
+	orderUseCase *OrderUseCase, payment service.PaymentProvider, checkoutMode string,
+	minTopUp, maxTopUp int64) *WalletUseCase {
+	return &WalletUseCase{
+		DB:                 db,
+		Log:                logger,
+		Validator:          validator,
+		WalletRepository:   walletRepository,
+		CustomerRepository: customerRepository,
+		InvoiceCounter:     invoiceCounter,
+		InvoiceFormat:      invoiceFormat,
+		OrderUseCase:       orderUseCase,
+		Payment:            payment,
+		CheckoutMode:       checkoutMode,
+		MinTopUp:           minTopUp,
+		MaxTopUp:           maxTopUp,
+	}
+}
+
+// IsTopUpInvoice cek apakah order_id dari notifikasi Midtrans milik top up wallet
+func (w *WalletUseCase) IsTopUpInvoice(invoiceNumber string) bool {
+	return strings.HasPrefix(invoiceNumber, w.InvoiceFormat.Prefix+"-")
+}
+
+func (w *WalletUseCase) FindByCustomerID(ctx context.Context, customerID string) (*model.WalletResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	id, err := uuid.Parse(customerID)
+	if err != nil {
+		return nil, utils.ErrUnauthorized
+	}
+
+	return w.findWallet(ctx, id)
+}
+
+// FindByCustomerIDForCMS sama dengan FindByCustomerID, tapi customer harus ada
+func (w *WalletUseCase) FindByCustomerIDForCMS(ctx context.Context, customerID string) (*model.WalletResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	id, err := w.findCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return w.findWallet(ctx, id)
+}
+
+func (w *WalletUseCase) FindEntries(ctx context.Context, customerID string, pagination *utils.PaginationRequest) ([]model.WalletEntryResponse, *utils.PaginationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	id, err := uuid.Parse(customerID)
+	if err != nil {
+		return nil, nil, utils.ErrUnauthorized
+	}
+
+	responses, paginationRes, err := w.findEntries(ctx, id, pagination)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := range responses {
+		responses[i].Actor = ""
+	}
+
+	return responses, paginationRes, nil
+}
+
+func (w *WalletUseCase) FindEntriesForCMS(ctx context.Context, customerID string, pagination *utils.PaginationRequest) ([]model.WalletEntryResponse, *utils.PaginationResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	id, err := w.findCustomerID(ctx, customerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return w.findEntries(ctx, id, pagination)
+}
+
+// TopUp buat transaksi isi saldo di Midtrans, saldo baru bertambah setelah notifikasi settlement
+func (w *WalletUseCase) TopUp(ctx context.Context, customerID string, request *model.TopUpWalletRequest) (*model.WalletTopUpResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	err := w.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(w.Validator.Translator))
+			}
+			return nil, fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return nil, fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	id, err := uuid.Parse(customerID)
+	if err != nil {
+		return nil, utils.ErrUnauthorized
+	}
+
+	if request.Amount < w.MinTopUp || (w.MaxTopUp > 0 && request.Amount > w.MaxTopUp) {
+		return nil, fmt.Errorf("%w: top up amount must be between %d and %d", utils.ErrValidation, w.MinTopUp, w.MaxTopUp)
+	}
+
+	checkoutMode := request.CheckoutMode
+	if checkoutMode == "" {
+		checkoutMode = w.CheckoutMode
+	}
+	if checkoutMode == entity.CheckoutModeCore && request.PaymentMethod == "" {
+		return nil, fmt.Errorf("%w: payment_method is required", utils.ErrValidation)
+	}
+	if request.PaymentMethod == entity.PaymentMethodWallet {
+		return nil, fmt.Errorf("%w: wallet cannot be topped up with wallet balance", utils.ErrValidation)
+	}
+
+	customer, err := w.CustomerRepository.FindById(w.DB.WithContext(ctx), &entity.Customer{}, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, utils.ErrUnauthorized
+		}
+		w.Log.Warnf("Failed find customer from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	invoiceNumber, err := w.nextInvoiceNumber(ctx)
+	if err != nil {
+		w.Log.Warnf("Failed generate top up invoice number : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	// top up dikirim ke Midtrans sebagai satu item, tanpa pajak & service charge
+	chargeRequest := &model.CreateOrderRequest{
+		CustomerID:    customer.ID.String(),
+		CustomerName:  customer.Name,
+		CustomerEmail: customer.Email,
+		CustomerPhone: customer.PhoneNumber,
+		PaymentMethod: request.PaymentMethod,
+		CheckoutMode:  checkoutMode,
+	}
+	pricing := &service.PricingResult{
+		Lines:    []service.PricingLine{{ID: "TOPUP", Name: "Wallet Top Up", Price: request.Amount, Qty: 1}},
+		Subtotal: request.Amount,
+		Total:    request.Amount,
+	}
+
+	var payment *paymentResult
+	if checkoutMode == entity.CheckoutModeSnap {
+		payment, err = w.OrderUseCase.createSnap(chargeRequest, invoiceNumber, pricing)
+	} else {
+		payment, err = w.OrderUseCase.chargeCoreAPI(chargeRequest, invoiceNumber, pricing)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// ✅ Transaksi Midtrans sudah terbentuk, kalau top up gagal disimpan transaksinya dibatalkan
+	committed := false
+	defer func() {
+		if !committed {
+			w.OrderUseCase.cancelOrphanPayment(invoiceNumber)
+		}
+	}()
+
+	topUp := &entity.WalletTopUp{
+		CustomerID:    customer.ID,
+		InvoiceNumber: invoiceNumber,
+		Amount:        request.Amount,
+		Status:        entity.TopUpStatusPending,
+		PaymentMethod: request.PaymentMethod,
+		PaymentType:   payment.PaymentType,
+		TransactionID: payment.TransactionID,
+		CheckoutMode:  checkoutMode,
+		SnapToken:     payment.SnapToken,
+		RedirectURL:   payment.RedirectURL,
+		QRString:      payment.QRString,
+		QRURL:         payment.QRURL,
+		DeeplinkURL:   payment.DeeplinkURL,
+		VANumber:      payment.VANumber,
+		VABank:        payment.VABank,
+		ExpiredAt:     payment.ExpiredAt,
+	}
+
+	tx := w.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	if err := w.WalletRepository.CreateTopUp(tx, topUp); err != nil {
+		w.Log.Warnf("Failed create top up to database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	// charge bisa langsung settle (ex: kartu), saldo langsung masuk
+	status := utils.MapMidtransStatus(payment.TransactionStatus, payment.FraudStatus)
+	if err := w.applyTopUpStatus(tx, topUp, status, entity.ActorMidtransCharge); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		w.Log.Warnf("Failed commit transaction : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	committed = true
+
+	return converter.WalletTopUpToResponse(topUp), nil
+}
+
+// HandleTopUpNotification notifikasi Midtrans untuk top up, saldo hanya ditambah sekali walaupun notifikasi dikirim ulang
+func (w *WalletUseCase) HandleTopUpNotification(ctx context.Context, request *model.MidtransNotificationRequest) error {
+	err := w.Validator.Validate.Struct(request)
+	if err != nil {
+		if validationErrors, ok := err.(validator.ValidationErrors); ok {
+
+			var messages []string
+			for _, e := range validationErrors {
+				messages = append(messages, e.Translate(w.Validator.Translator))
+			}
+			return fmt.Errorf("%w: %s", utils.ErrValidation, strings.Join(messages, ", "))
+		}
+		return fmt.Errorf("%w: %s", utils.ErrValidation, err.Error())
+	}
+
+	if !w.Payment.VerifySignature(request.OrderID, request.StatusCode, request.GrossAmount, request.SignatureKey) {
+		w.Log.Warnf("Invalid midtrans signature, order_id=%s", request.OrderID)
+		return utils.ErrInvalidSignature
+	}
+
+	tx := w.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	topUp, err := w.WalletRepository.FindTopUpByInvoiceForUpdate(tx, request.OrderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			w.Log.Infof("top up not found, invoice=%s", request.OrderID)
+			return utils.ErrNotFound
+		}
+		w.Log.Warnf("Failed find top up from database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	if request.TransactionID != "" {
+		topUp.TransactionID = request.TransactionID
+	}
+	if request.PaymentType != "" {
+		topUp.PaymentType = request.PaymentType
+	}
+
+	status := utils.MapMidtransStatus(request.TransactionStatus, request.FraudStatus)
+	if err := w.applyTopUpStatus(tx, topUp, status, entity.ActorMidtransNotification); err != nil {
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		w.Log.Warnf("Failed commit transaction : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return nil
+}
+
+// applyTopUpStatus status top up hanya bisa pindah dari pending, jadi saldo tidak mungkin dikredit dua kali
+func (w *WalletUseCase) applyTopUpStatus(tx *gorm.DB, topUp *entity.WalletTopUp, status, actor string) error {
+	if topUp.Status == entity.TopUpStatusPending {
+		switch status {
+		case entity.OrderStatusPaid:
+			entry := &entity.WalletEntry{
+				Type:           entity.WalletEntryCredit,
+				Amount:         topUp.Amount,
+				Source:         entity.WalletSourceTopUp,
+				ReferenceID:    topUp.InvoiceNumber,
+				CounterAccount: entity.WalletAccountPaymentGateway,
+				Description:    "top up " + topUp.InvoiceNumber,
+				Actor:          actor,
+			}
+			if _, err := postWalletEntry(tx, w.WalletRepository, topUp.CustomerID, entry); err != nil {
+				w.Log.Warnf("Failed credit wallet, invoice=%s : %+v", topUp.InvoiceNumber, err)
+				return err
+			}
+			now := time.Now()
+			topUp.Status, topUp.PaidAt = entity.TopUpStatusPaid, &now
+		case entity.OrderStatusFailed:
+			topUp.Status = entity.TopUpStatusFailed
+		case entity.OrderStatusExpired:
+			topUp.Status = entity.TopUpStatusExpired
+		case entity.OrderStatusCancelled:
+			topUp.Status = entity.TopUpStatusCancelled
+		}
+	} else if status != topUp.Status {
+		w.Log.Warnf("Ignore midtrans status %s for top up invoice=%s with status %s", status, topUp.InvoiceNumber, topUp.Status)
+	}
+
+	if err := w.WalletRepository.UpdateTopUp(tx, topUp); err != nil {
+		w.Log.Warnf("Failed update top up to database : %+v", err)
+		return fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	return nil
+}
+
+// ExpirePendingTopUps cek top up pending yang sudah lewat ExpiredAt ke Midtrans, lalu pindahkan ke status sebenarnya
+func (w *WalletUseCase) ExpirePendingTopUps(ctx context.Context, limit int) (int, error) {
+	topUps, err := w.WalletRepository.FindExpiredPendingTopUps(w.DB.WithContext(ctx), time.Now(), limit)
+	if err != nil {
+		w.Log.Warnf("Failed find expired pending top ups : %+v", err)
+		return 0, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	moved := 0
+	for _, topUp := range topUps {
+		if err := w.expireTopUp(ctx, topUp.InvoiceNumber); err != nil {
+			w.Log.Warnf("Failed expire top up, invoice=%s : %+v", topUp.InvoiceNumber, err)
+			continue
+		}
+		moved++
+	}
+
+	return moved, nil
+}
+
+func (w *WalletUseCase) expireTopUp(ctx context.Context, invoiceNumber string) error {
+	var transactionID, paymentType, status string
+
+	resp, err := w.Payment.Status(invoiceNumber)
+	switch {
+	case err == nil:
+		transactionID, paymentType = resp.TransactionID, resp.PaymentType
+		status = utils.MapMidtransStatus(resp.TransactionStatus, resp.FraudStatus)
+	case errors.Is(err, utils.ErrPaymentNotFound):
+		// transaksi tidak pernah tercatat di Midtrans, anggap expire
+		status = entity.OrderStatusExpired
+	default:
+		return fmt.Errorf("%w: %v", utils.ErrIntegration, err)
+	}
+	if status == entity.OrderStatusPending {
+		// sudah lewat waktu bayar tapi di Midtrans masih pending
+		status = entity.OrderStatusExpired
+	}
+
+	moved, err := w.syncPendingTopUp(ctx, invoiceNumber, transactionID, paymentType, status, entity.ActorExpiryWorker)
+	if err != nil {
+		return err
+	}
+	if moved {
+		w.Log.Infof("Top up %s moved to %s by expiry worker", invoiceNumber, status)
+	}
+	return nil
+}
+
+// ReconcilePendingTopUp samakan top up yang masih pending dengan status transaksi terbaru dari provider
+func (w *WalletUseCase) ReconcilePendingTopUp(ctx context.Context, invoiceNumber string, resp *coreapi.TransactionStatusResponse, actor string) (bool, error) {
+	status := utils.MapMidtransStatus(resp.TransactionStatus, resp.FraudStatus)
+	return w.syncPendingTopUp(ctx, invoiceNumber, resp.TransactionID, resp.PaymentType, status, actor)
+}
+
+// syncPendingTopUp kunci top up lalu terapkan status dari provider, hanya kalau top up masih pending.
+// Return false kalau top up sudah berubah duluan (ex: keburu diupdate notifikasi Midtrans).
+func (w *WalletUseCase) syncPendingTopUp(ctx context.Context, invoiceNumber, transactionID, paymentType, status, actor string) (bool, error) {
+	tx := w.DB.WithContext(ctx).Begin()
+	defer tx.Rollback()
+
+	topUp, err := w.WalletRepository.FindTopUpByInvoiceForUpdate(tx, invoiceNumber)
+	if err != nil {
+		return false, err
+	}
+	if topUp.Status != entity.TopUpStatusPending {
+		return false, nil
+	}
+
+	if transactionID != "" {
+		topUp.TransactionID = transactionID
+	}
+	if paymentType != "" {
+		topUp.PaymentType = paymentType
+	}
+	if err := w.applyTopUpStatus(tx, topUp, status, actor); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return topUp.Status != entity.TopUpStatusPending, nil
+}
+
+func (w *WalletUseCase) findWallet(ctx context.Context, customerID uuid.UUID) (*model.WalletResponse, error) {
+	wallet, err := w.WalletRepository.FindByCustomerID(w.DB.WithContext(ctx), customerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// belum pernah top up, saldo 0
+			return converter.WalletToResponse(&entity.Wallet{CustomerID: customerID}), nil
+		}
+		w.Log.Warnf("Failed find wallet from database : %+v", err)
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return converter.WalletToResponse(wallet), nil
+}
+
+func (w *WalletUseCase) findEntries(ctx context.Context, customerID uuid.UUID, pagination *utils.PaginationRequest) ([]model.WalletEntryResponse, *utils.PaginationResponse, error) {
+	paginationRes := &utils.PaginationResponse{
+		Page:    pagination.Page,
+		Limit:   pagination.Limit,
+		OrderBy: "created_at",
+		SortBy:  "desc",
+	}
+
+	wallet, err := w.WalletRepository.FindByCustomerID(w.DB.WithContext(ctx), customerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return []model.WalletEntryResponse{}, paginationRes, nil
+		}
+		w.Log.Warnf("Failed find wallet from database : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	entries, total, err := w.WalletRepository.FindEntries(w.DB.WithContext(ctx), wallet.ID, pagination)
+	if err != nil {
+		w.Log.Warnf("Failed find wallet entries from database : %+v", err)
+		return nil, nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	responses := make([]model.WalletEntryResponse, len(entries))
+	for i, entry := range entries {
+		responses[i] = *converter.WalletEntryToResponse(&entry)
+	}
+
+	paginationRes.TotalData = total
+	paginationRes.TotalPage = int((total + int64(pagination.Limit) - 1) / int64(pagination.Limit))
+
+	return responses, paginationRes, nil
+}
+
+func (w *WalletUseCase) findCustomerID(ctx context.Context, customerID string) (uuid.UUID, error) {
+	id, err := uuid.Parse(customerID)
+	if err != nil {
+		return uuid.Nil, utils.ErrNotFound
+	}
+
+	if _, err := w.CustomerRepository.FindById(w.DB.WithContext(ctx), &entity.Customer{}, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			w.Log.Infof("customer not found, id=%s", customerID)
+			return uuid.Nil, utils.ErrNotFound
+		}
+		w.Log.Warnf("Failed find customer from database : %+v", err)
+		return uuid.Nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return id, nil
+}
+
+// nextInvoiceNumber counter harian sendiri per prefix, sama seperti nomor invoice order
+func (w *WalletUseCase) nextInvoiceNumber(ctx context.Context) (string, error) {
+	now := time.Now()
+	sequence, err := w.InvoiceCounter.Next(w.DB.WithContext(ctx), w.InvoiceFormat, now, &entity.WalletTopUp{})
+	if err != nil {
+		return "", err
+	}
+	return w.InvoiceFormat.Generate(now, sequence), nil
+}
+
+// postWalletEntry lock wallet customer, ubah saldo lalu catat entry ledger-nya di transaksi yang sama.
+// Dipakai top up, pembayaran order & refund supaya saldo selalu sama dengan jumlah entry.
+func postWalletEntry(tx *gorm.DB, walletRepository *repository.WalletRepository, customerID uuid.UUID, entry *entity.WalletEntry) (*entity.Wallet, error) {
+	if entry.Amount <= 0 {
+		return nil, fmt.Errorf("%w: wallet entry amount must be greater than zero", utils.ErrValidation)
+	}
+
+	wallet, err := walletRepository.FindOrCreateForUpdate(tx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	switch entry.Type {
+	case entity.WalletEntryCredit:
+		wallet.Balance += entry.Amount
+	case entity.WalletEntryDebit:
+		if wallet.Balance < entry.Amount {
+			return nil, fmt.Errorf("%w: balance %d, required %d", utils.ErrInsufficientBalance, wallet.Balance, entry.Amount)
+		}
+		wallet.Balance -= entry.Amount
+	default:
+		return nil, fmt.Errorf("%w: unknown wallet entry type %s", utils.ErrInternal, entry.Type)
+	}
+
+	entry.WalletID = wallet.ID
+	entry.BalanceAfter = wallet.Balance
+	if err := walletRepository.Update(tx, wallet); err != nil {
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+	if err := walletRepository.CreateEntry(tx, entry); err != nil {
+		return nil, fmt.Errorf("%w: %s", utils.ErrInternal, err.Error())
+	}
+
+	return wallet, nil
+}
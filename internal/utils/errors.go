@@ -1,6 +1,12 @@
 package utils
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
+)
 
 var (
 	// Client errors
@@ -12,6 +18,12 @@ var (
 	ErrTooManyRequest  = errors.New("too many requests") // rate limit / throttle
 	ErrInvalidPassword = errors.New("invalid password")  // rate limit / throttle
 	ErrInvalidEmail    = errors.New("invalid email")     // rate limit / throttle
+	ErrPriceChanged    = errors.New("price changed")     // harga di cart client sudah berubah
+	ErrOutOfStock      = errors.New("out of stock")      // stok tidak cukup
+
+	ErrInsufficientBalance = errors.New("insufficient balance") // saldo wallet tidak cukup
+
+	ErrInvalidStatusTransition = errors.New("invalid status transition") // perpindahan status order tidak diizinkan
 
 	// Server errors
 	ErrInternal    = errors.New("internal server error") // kesalahan server
@@ -26,4 +38,35 @@ var (
 	ErrPaymentPending   = errors.New("payment pending")         // masih menunggu pembayaran
 	ErrIntegration      = errors.New("integration error")       // error komunikasi dengan 3rd party
 	ErrInvalidSignature = errors.New("invalid signature error") // signature tidak cocok (security)
+	ErrPaymentNotFound  = errors.New("payment not found")       // transaksi tidak dikenal oleh provider
 )
+
+// PriceChangedError berisi daftar item yang harganya sudah berubah, supaya client bisa refresh cart
+type PriceChangedError struct {
+	Items []model.PriceChangedItem
+}
+
+func (e *PriceChangedError) Error() string {
+	return fmt.Sprintf("%s: %d item(s)", ErrPriceChanged.Error(), len(e.Items))
+}
+
+func (e *PriceChangedError) Unwrap() error {
+	return ErrPriceChanged
+}
+
+// OutOfStockError berisi daftar item yang stoknya kurang
+type OutOfStockError struct {
+	Items []model.OutOfStockItem
+}
+
+func (e *OutOfStockError) Error() string {
+	details := make([]string, len(e.Items))
+	for i, item := range e.Items {
+		details[i] = fmt.Sprintf("%s (requested %d, available %d)", item.Name, item.Requested, item.Available)
+	}
+	return fmt.Sprintf("%s: %s", ErrOutOfStock.Error(), strings.Join(details, ", "))
+}
+
+func (e *OutOfStockError) Unwrap() error {
+	return ErrOutOfStock
+}
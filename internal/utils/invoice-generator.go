@@ -5,7 +5,24 @@ import (
 	"time"
 )
 
-func GenerateInvoice(counter int) string {
-	date := time.Now().Format("20060102") // YYYYMMDD
-	return fmt.Sprintf("INV-%s-%04d", date, counter)
+// InvoiceFormat format nomor invoice, ex: INV-20250908-0001
+type InvoiceFormat struct {
+	Prefix     string
+	DateFormat string // layout Go, ex: 20060102
+	Digits     int    // jumlah digit nomor urut, di-pad dengan 0
+	Location   *time.Location
+}
+
+// Day tanggal bisnis (timezone toko), dipakai sebagai key counter harian
+func (f *InvoiceFormat) Day(now time.Time) string {
+	return now.In(f.Location).Format("2006-01-02")
+}
+
+// NumberPrefix bagian nomor invoice sebelum nomor urut, ex: INV-20250908-
+func (f *InvoiceFormat) NumberPrefix(now time.Time) string {
+	return f.Prefix + "-" + now.In(f.Location).Format(f.DateFormat) + "-"
+}
+
+func (f *InvoiceFormat) Generate(now time.Time, sequence int64) string {
+	return fmt.Sprintf("%s%0*d", f.NumberPrefix(now), f.Digits, sequence)
 }
@@ -1,11 +1,19 @@
 package utils
 
 import (
+	"crypto/sha512"
+	"crypto/subtle"
 	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
+	"github.com/midtrans/midtrans-go/snap"
+	"github.com/ojihalawa/daily-coffee-api.git/internal/entity"
 	"github.com/ojihalawa/daily-coffee-api.git/internal/model"
 )
 
@@ -24,6 +32,66 @@ func GetMidtransAuthHeader(serverKey string) string {
 	return fmt.Sprintf("Basic %s", auth)
 }
 
+// VerifyMidtransSignature cek signature_key notifikasi: SHA512(order_id + status_code + gross_amount + server_key)
+func VerifyMidtransSignature(orderID, statusCode, grossAmount, serverKey, signature string) bool {
+	hash := sha512.Sum512([]byte(orderID + statusCode + grossAmount + serverKey))
+	expected := hex.EncodeToString(hash[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(strings.ToLower(signature))) == 1
+}
+
+// MapMidtransStatus normalisasi transaction_status Midtrans ke status order kita
+func MapMidtransStatus(transactionStatus, fraudStatus string) string {
+	switch transactionStatus {
+	case "capture":
+		switch fraudStatus {
+		case "challenge":
+			return entity.OrderStatusPending
+		case "deny":
+			return entity.OrderStatusFailed
+		}
+		return entity.OrderStatusPaid
+	case "settlement", "partial_refund", "partial_chargeback":
+		// refund sebagian tidak mengubah status, nominalnya dicatat di order
+		return entity.OrderStatusPaid
+	case "deny", "failure":
+		return entity.OrderStatusFailed
+	case "cancel":
+		return entity.OrderStatusCancelled
+	case "expire":
+		return entity.OrderStatusExpired
+	case "refund", "chargeback":
+		return entity.OrderStatusRefunded
+	default:
+		return entity.OrderStatusPending
+	}
+}
+
+// midtransLocation semua waktu dari Midtrans pakai GMT+7
+var midtransLocation = time.FixedZone("WIB", 7*60*60)
+
+// ParseMidtransTime parse format waktu Midtrans (ex: expiry_time), nil kalau kosong / tidak valid
+func ParseMidtransTime(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.ParseInLocation("2006-01-02 15:04:05", value, midtransLocation)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// ExtractMidtransVA nomor virtual account dari response bank transfer, permata punya field sendiri
+func ExtractMidtransVA(resp *coreapi.ChargeResponse) (bank, vaNumber string) {
+	if resp.PermataVaNumber != "" {
+		return string(midtrans.BankPermata), resp.PermataVaNumber
+	}
+	if len(resp.VaNumbers) > 0 {
+		return resp.VaNumbers[0].Bank, resp.VaNumbers[0].VANumber
+	}
+	return "", ""
+}
+
 func ExtractMidtransURLs(resp *coreapi.ChargeResponse) (qrURL, deeplinkURL string) {
 	for _, action := range resp.Actions {
 		switch action.Name {
@@ -36,13 +104,18 @@ func ExtractMidtransURLs(resp *coreapi.ChargeResponse) (qrURL, deeplinkURL strin
 	return
 }
 
-// BuildChargeReq mapping payload user → coreapi.ChargeReq
-func BuildChargeReq(req *model.CreateOrderRequest, invoiceNumber string, totalAmount int64) (*coreapi.ChargeReq, error) {
+// BuildChargeReq mapping payload user → coreapi.ChargeReq, hanya untuk payment method yang didukung provider
+func BuildChargeReq(req *model.CreateOrderRequest, invoiceNumber string, totalAmount int64, items []midtrans.ItemDetails, supportedMethods []string) (*coreapi.ChargeReq, error) {
+	if !slices.Contains(supportedMethods, req.PaymentMethod) {
+		return nil, fmt.Errorf("%w: unsupported payment method: %s", ErrValidation, req.PaymentMethod)
+	}
+
 	transaction := &coreapi.ChargeReq{
 		TransactionDetails: midtrans.TransactionDetails{
 			OrderID:  invoiceNumber,
 			GrossAmt: totalAmount,
 		},
+		Items: &items,
 		CustomerDetails: &midtrans.CustomerDetails{
 			FName: req.CustomerName,
 			Email: req.CustomerEmail,
@@ -74,8 +147,59 @@ func BuildChargeReq(req *model.CreateOrderRequest, invoiceNumber string, totalAm
 		}
 
 	default:
-		return nil, fmt.Errorf("unsupported payment method: %s", req.PaymentMethod)
+		return nil, fmt.Errorf("%w: unsupported payment method: %s", ErrValidation, req.PaymentMethod)
+	}
+
+	return transaction, nil
+}
+
+// SnapExpiryDuration batas waktu bayar transaksi Snap, dihitung dari transaksi dibuat
+const SnapExpiryDuration = 60 * time.Minute
+
+var snapPaymentTypes = map[string]snap.SnapPaymentType{
+	"gopay":     snap.PaymentTypeGopay,
+	"shopeepay": snap.PaymentTypeShopeepay,
+	"qris":      snap.SnapPaymentType("other_qris"),
+	"bca":       snap.PaymentTypeBCAVA,
+	"bni":       snap.PaymentTypeBNIVA,
+	"bri":       snap.PaymentTypeBRIVA,
+	"permata":   snap.PaymentTypePermataVA,
+}
+
+// BuildSnapReq request Snap, payment method kosong berarti customer pilih sendiri di halaman Snap
+// dari metode yang kita support
+func BuildSnapReq(req *model.CreateOrderRequest, invoiceNumber string, totalAmount int64, items []midtrans.ItemDetails, supportedMethods []string, now time.Time) (*snap.Request, error) {
+	transaction := &snap.Request{
+		TransactionDetails: midtrans.TransactionDetails{
+			OrderID:  invoiceNumber,
+			GrossAmt: totalAmount,
+		},
+		Items: &items,
+		CustomerDetail: &midtrans.CustomerDetails{
+			FName: req.CustomerName,
+			Email: req.CustomerEmail,
+			Phone: req.CustomerPhone,
+		},
+		Expiry: &snap.ExpiryDetails{
+			StartTime: now.In(midtransLocation).Format("2006-01-02 15:04:05 -0700"),
+			Unit:      "minute",
+			Duration:  int64(SnapExpiryDuration / time.Minute),
+		},
 	}
 
+	if req.PaymentMethod != "" {
+		paymentType, ok := snapPaymentTypes[req.PaymentMethod]
+		if !ok || !slices.Contains(supportedMethods, req.PaymentMethod) {
+			return nil, fmt.Errorf("%w: unsupported payment method: %s", ErrValidation, req.PaymentMethod)
+		}
+		transaction.EnabledPayments = []snap.SnapPaymentType{paymentType}
+		return transaction, nil
+	}
+
+	for _, method := range supportedMethods {
+		if paymentType, ok := snapPaymentTypes[method]; ok {
+			transaction.EnabledPayments = append(transaction.EnabledPayments, paymentType)
+		}
+	}
 	return transaction, nil
 }
@@ -0,0 +1,196 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// StoreInfo identitas toko yang dicetak di invoice / receipt
+type StoreInfo struct {
+	Name    string
+	Address string
+	Phone   string
+	Email   string
+	TaxID   string // NPWP
+}
+
+// OrderDocument isi invoice / receipt yang sudah siap cetak, semua teks & waktu sudah diformat oleh caller
+type OrderDocument struct {
+	Title           string // INVOICE, RECEIPT
+	Store           StoreInfo
+	InvoiceNumber   string
+	Status          string
+	IssuedAt        string
+	PaidAt          string
+	CustomerName    string
+	CustomerEmail   string
+	CustomerPhone   string
+	ShippingAddress string
+	PaymentMethod   string
+	Items           []OrderDocumentItem
+	Summary         []OrderDocumentLine // subtotal, diskon, service, pajak, ongkir, pembulatan
+	Total           int64
+	Footer          []string
+}
+
+type OrderDocumentItem struct {
+	Name     string
+	Qty      int
+	Price    int64
+	Subtotal int64
+}
+
+type OrderDocumentLine struct {
+	Label  string
+	Amount int64
+}
+
+const (
+	docMargin     = 40.0
+	docRight      = PDFPageWidth - docMargin
+	docFontSize   = 9.0
+	docLineHeight = 14.0
+	docBottom     = PDFPageHeight - 60
+	docNameLength = 44
+)
+
+// RenderOrderDocument cetak invoice / receipt ke PDF A4
+func RenderOrderDocument(doc *OrderDocument) []byte {
+	r := &documentRenderer{pdf: NewPDFWriter(), y: docMargin + 10}
+
+	// header toko
+	r.pdf.Text(docMargin, r.y, 16, true, doc.Store.Name)
+	r.pdf.TextRight(docRight, r.y, 16, true, doc.Title)
+	r.y += docLineHeight * 1.5
+	for _, line := range []string{doc.Store.Address, doc.Store.Phone, doc.Store.Email} {
+		if line != "" {
+			r.text(line)
+		}
+	}
+	if doc.Store.TaxID != "" {
+		r.text("NPWP " + doc.Store.TaxID)
+	}
+	r.rule()
+
+	// info order
+	r.pair("Invoice No", doc.InvoiceNumber)
+	r.pair("Date", doc.IssuedAt)
+	if doc.PaidAt != "" {
+		r.pair("Paid At", doc.PaidAt)
+	}
+	r.pair("Status", strings.ToUpper(doc.Status))
+	r.pair("Payment", doc.PaymentMethod)
+	r.y += docLineHeight / 2
+
+	r.bold("Bill To")
+	for _, line := range []string{doc.CustomerName, doc.CustomerEmail, doc.CustomerPhone, doc.ShippingAddress} {
+		if line != "" {
+			r.text(line)
+		}
+	}
+	r.rule()
+
+	// item
+	r.itemHeader()
+	for _, item := range doc.Items {
+		if r.y > docBottom {
+			r.newPage()
+			r.itemHeader()
+		}
+		r.pdf.Text(docMargin, r.y, docFontSize, false, truncateRunes(item.Name, docNameLength))
+		r.pdf.TextRight(360, r.y, docFontSize, false, strconv.Itoa(item.Qty))
+		r.pdf.TextRight(450, r.y, docFontSize, false, FormatRupiah(item.Price))
+		r.pdf.TextRight(docRight, r.y, docFontSize, false, FormatRupiah(item.Subtotal))
+		r.y += docLineHeight
+	}
+	r.rule()
+
+	// ringkasan harga
+	for _, line := range doc.Summary {
+		if r.y > docBottom {
+			r.newPage()
+		}
+		r.pdf.TextRight(450, r.y, docFontSize, false, line.Label)
+		r.pdf.TextRight(docRight, r.y, docFontSize, false, FormatRupiah(line.Amount))
+		r.y += docLineHeight
+	}
+	r.pdf.TextRight(450, r.y, docFontSize+1, true, "TOTAL")
+	r.pdf.TextRight(docRight, r.y, docFontSize+1, true, FormatRupiah(doc.Total))
+	r.y += docLineHeight * 2
+
+	for _, line := range doc.Footer {
+		if r.y > docBottom {
+			r.newPage()
+		}
+		r.text(line)
+	}
+
+	return r.pdf.Bytes()
+}
+
+type documentRenderer struct {
+	pdf *PDFWriter
+	y   float64
+}
+
+func (r *documentRenderer) text(text string) {
+	r.pdf.Text(docMargin, r.y, docFontSize, false, text)
+	r.y += docLineHeight
+}
+
+func (r *documentRenderer) bold(text string) {
+	r.pdf.Text(docMargin, r.y, docFontSize, true, text)
+	r.y += docLineHeight
+}
+
+func (r *documentRenderer) pair(label, value string) {
+	r.pdf.Text(docMargin, r.y, docFontSize, true, label)
+	r.pdf.Text(docMargin+90, r.y, docFontSize, false, value)
+	r.y += docLineHeight
+}
+
+func (r *documentRenderer) rule() {
+	r.y += docLineHeight / 4
+	r.pdf.Line(docMargin, r.y-docLineHeight/2, docRight, r.y-docLineHeight/2)
+	r.y += docLineHeight / 2
+}
+
+func (r *documentRenderer) itemHeader() {
+	r.pdf.Text(docMargin, r.y, docFontSize, true, "Item")
+	r.pdf.TextRight(360, r.y, docFontSize, true, "Qty")
+	r.pdf.TextRight(450, r.y, docFontSize, true, "Price")
+	r.pdf.TextRight(docRight, r.y, docFontSize, true, "Amount")
+	r.y += docLineHeight
+}
+
+func (r *documentRenderer) newPage() {
+	r.pdf.AddPage()
+	r.y = docMargin + 10
+}
+
+// FormatRupiah ex: 25000 -> "Rp 25.000", -5000 -> "-Rp 5.000"
+func FormatRupiah(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	digits := strconv.FormatInt(amount, 10)
+	var out strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			out.WriteByte('.')
+		}
+		out.WriteRune(d)
+	}
+	return sign + "Rp " + out.String()
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) > limit {
+		return string(runes[:limit-3]) + "..."
+	}
+	return text
+}
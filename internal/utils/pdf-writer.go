@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ukuran A4 dalam point (1/72 inch)
+const (
+	PDFPageWidth  = 595.28
+	PDFPageHeight = 841.89
+)
+
+// lebar karakter font Courier = 0.6 x ukuran font, jadi perataan kanan cukup dihitung dari jumlah karakter
+const pdfCourierWidth = 0.6
+
+// PDFWriter generator PDF minimal (teks & garis, font standar Courier) tanpa library eksternal.
+// Outputnya deterministik: tidak ada tanggal pembuatan atau ID acak, input sama selalu menghasilkan byte yang sama.
+// Koordinat y dihitung dari atas halaman.
+type PDFWriter struct {
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+}
+
+func NewPDFWriter() *PDFWriter {
+	w := &PDFWriter{}
+	w.AddPage()
+	return w
+}
+
+func (w *PDFWriter) AddPage() {
+	w.current = &bytes.Buffer{}
+	w.pages = append(w.pages, w.current)
+}
+
+func (w *PDFWriter) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(w.current, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font, pdfNumber(size), pdfNumber(x), pdfNumber(PDFPageHeight-y), pdfEscape(text))
+}
+
+// TextRight tulis teks rata kanan, right = posisi x ujung kanan teks
+func (w *PDFWriter) TextRight(right, y, size float64, bold bool, text string) {
+	w.Text(right-PDFTextWidth(text, size), y, size, bold, text)
+}
+
+func (w *PDFWriter) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(w.current, "0.5 w %s %s m %s %s l S\n",
+		pdfNumber(x1), pdfNumber(PDFPageHeight-y1), pdfNumber(x2), pdfNumber(PDFPageHeight-y2))
+}
+
+// Bytes susun dokumen: catalog, pages, 2 font, lalu page + content per halaman
+func (w *PDFWriter) Bytes() []byte {
+	var objects []string
+
+	kids := make([]string, len(w.pages))
+	for i := range w.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+
+	objects = append(objects,
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(w.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>",
+	)
+	for i, page := range w.pages {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pdfNumber(PDFPageWidth), pdfNumber(PDFPageHeight), 6+i*2),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()),
+		)
+	}
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return out.Bytes()
+}
+
+func PDFTextWidth(text string, size float64) float64 {
+	return float64(len([]rune(text))) * size * pdfCourierWidth
+}
+
+// pdfNumber dibulatkan 2 desimal, jadi sisa pembulatan float (ex: 506.67999999999995) tidak ikut tercetak
+// dan output tetap sama di semua arsitektur
+func pdfNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
+// pdfEscape ubah teks ke WinAnsi, karakter di luar Latin-1 diganti "?"
+func pdfEscape(text string) string {
+	var buf bytes.Buffer
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r >= 32 && r < 127:
+			buf.WriteRune(r)
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&buf, "\\%03o", r)
+		default:
+			buf.WriteByte('?')
+		}
+	}
+	return buf.String()
+}
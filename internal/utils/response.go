@@ -49,6 +49,16 @@ func SuccessResponseWithPagination(code int, message string, data interface{}, p
 	}
 }
 
+// ErrorResponseWithData for errors that carry details for the client
+func ErrorResponseWithData(code int, message string, data interface{}) fiber.Map {
+	return fiber.Map{
+		"code":    code,
+		"status":  false,
+		"message": message,
+		"errors":  data,
+	}
+}
+
 // ErrorResponse for handling errors
 func ErrorResponse(code int, message string) fiber.Map {
 	return fiber.Map{
@@ -13,3 +13,11 @@ func MustParseUUID(id string) uuid.UUID {
 	}
 	return parsed
 }
+
+func MustParseUUIDs(ids []string) []uuid.UUID {
+	parsed := make([]uuid.UUID, len(ids))
+	for i, id := range ids {
+		parsed[i] = MustParseUUID(id)
+	}
+	return parsed
+}